@@ -13,6 +13,7 @@ import (
 	"syscall"
 	"time"
 
+	_ "github.com/abenz1267/elephant/v2/internal/builtin"
 	"github.com/abenz1267/elephant/v2/internal/comm"
 	"github.com/abenz1267/elephant/v2/internal/comm/client"
 	"github.com/abenz1267/elephant/v2/internal/install"
@@ -122,9 +123,13 @@ WantedBy=graphical-session.target
 
 					providers.Load(false)
 
-					for _, v := range providers.Providers {
+					for _, v := range providers.All() {
 						if *v.Name == "menus" {
-							for _, m := range common.Menus {
+							for _, m := range common.AllMenus() {
+								if !m.Visible() {
+									continue
+								}
+
 								fmt.Printf("%s;menus:%s\n", m.NamePretty, m.Name)
 							}
 						} else {
@@ -132,9 +137,142 @@ WantedBy=graphical-session.target
 						}
 					}
 
+					for _, v := range providers.Broken {
+						fmt.Fprintf(os.Stderr, "skipped %s: %s\n", v.Path, v.Error)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "provider",
+				Usage: "manage providers of the running service",
+				Commands: []*cli.Command{
+					{
+						Name:  "enable",
+						Usage: "enables a provider that was disabled or ignored",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.ProviderControl(cmd.StringArg("provider"), "enable")
+							return nil
+						},
+					},
+					{
+						Name:  "disable",
+						Usage: "disables a provider until it is enabled again or elephant restarts",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.ProviderControl(cmd.StringArg("provider"), "disable")
+							return nil
+						},
+					},
+					{
+						Name:  "reload",
+						Usage: "reloads the config of a provider",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.ProviderControl(cmd.StringArg("provider"), "reload")
+							return nil
+						},
+					},
+				},
+			},
+			{
+				Name:  "incognito",
+				Usage: "suspends recording history, clipboard and calculations. action is one of status, enable, disable, toggle",
+				Arguments: []cli.Argument{
+					&cli.StringArg{
+						Name: "action",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					client.Incognito(cmd.StringArg("action"))
 					return nil
 				},
 			},
+			{
+				Name:  "history",
+				Usage: "manage the history of the running service, of all providers if none is given",
+				Commands: []*cli.Command{
+					{
+						Name:  "list",
+						Usage: "lists items with their uses, last use and frecency",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.History("list", cmd.StringArg("provider"))
+							return nil
+						},
+					},
+					{
+						Name:  "prune",
+						Usage: "deletes entries exceeding history_max_age or history_max_entries",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.History("prune", cmd.StringArg("provider"))
+							return nil
+						},
+					},
+					{
+						Name:  "clear",
+						Usage: "deletes all entries",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.History("clear", cmd.StringArg("provider"))
+							return nil
+						},
+					},
+					{
+						Name:  "export",
+						Usage: "writes all entries as JSON to stdout",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.History("export", cmd.StringArg("provider"))
+							return nil
+						},
+					},
+					{
+						Name:  "import",
+						Usage: "reads entries as JSON from stdin",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "provider",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							client.History("import", cmd.StringArg("provider"))
+							return nil
+						},
+					},
+				},
+			},
 			{
 				Name:    "menu",
 				Aliases: []string{"m"},
@@ -149,6 +287,73 @@ WantedBy=graphical-session.target
 					return nil
 				},
 			},
+			{
+				Name:  "dmenu",
+				Usage: "reads items from stdin, opens them as a menu and prints the picked value. exits with 1 if nothing was picked",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Value: "dmenu",
+						Usage: "name of the menu, a later request with the same name replaces it",
+					},
+					&cli.StringFlag{
+						Name:    "prompt",
+						Aliases: []string{"p"},
+						Usage:   "shown as the name of the menu",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "read json items with text, subtext, value, icon and preview instead of lines",
+					},
+					&cli.IntFlag{
+						Name:  "timeout",
+						Usage: "seconds to wait for a pick, 0 waits forever",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					client.Dmenu(cmd.String("name"), cmd.String("prompt"), cmd.Bool("json"), cmd.Int("timeout"))
+					return nil
+				},
+			},
+			{
+				Name:  "menus",
+				Usage: "manage menu definitions",
+				Commands: []*cli.Command{
+					{
+						Name:  "validate",
+						Usage: "checks menu definitions in a file or directory, or all configured ones",
+						Arguments: []cli.Argument{
+							&cli.StringArg{
+								Name: "path",
+							},
+						},
+						Action: func(ctx context.Context, cmd *cli.Command) error {
+							logger := slog.New(slog.DiscardHandler)
+							slog.SetDefault(logger)
+
+							common.LoadGlobalConfig()
+
+							paths := []string{}
+
+							if p := cmd.StringArg("path"); p != "" {
+								paths = append(paths, p)
+							}
+
+							problems := common.ValidateMenus(paths...)
+
+							for _, v := range problems {
+								fmt.Println(v)
+							}
+
+							if len(problems) > 0 {
+								os.Exit(1)
+							}
+
+							return nil
+						},
+					},
+				},
+			},
 			{
 				Name:    "generatedoc",
 				Aliases: []string{"d"},
@@ -306,6 +511,7 @@ WantedBy=graphical-session.target
 
 			go func() {
 				<-signalChan
+				providers.Shutdown()
 				os.Remove(comm.Socket)
 				os.Exit(0)
 			}()
@@ -41,7 +41,6 @@ require (
 	github.com/sergi/go-diff v1.4.0 // indirect
 	github.com/yalue/native_endian v1.0.2 // indirect
 	golang.org/x/crypto v0.43.0 // indirect
-	golang.org/x/text v0.30.0 // indirect
 )
 
 require (
@@ -59,4 +58,5 @@ require (
 	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/net v0.46.0 // indirect
 	golang.org/x/sys v0.37.0 // indirect
+	golang.org/x/text v0.30.0
 )
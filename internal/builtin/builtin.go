@@ -0,0 +1,10 @@
+// Package builtin compiles the providers under internal/providers into the binary.
+//
+// The providers are plugins in package main, so they can't be imported directly. Running
+// `go generate` copies them into sub-packages of this one and adds an init registering each
+// of them via providers.Register. Building with `-tags builtin` links all of them in, without
+// the tag this package is empty. Plugins from the provider directories are still loaded, but
+// a built-in provider shadows a plugin with the same name.
+package builtin
+
+//go:generate go run gen.go
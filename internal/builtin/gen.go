@@ -0,0 +1,218 @@
+//go:build ignore
+
+// gen copies the providers into internal/builtin and generates their registration.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+const (
+	module = "github.com/abenz1267/elephant/v2"
+	src    = "../providers"
+)
+
+var (
+	required = []string{"Name", "NamePretty", "Available", "PrintDoc", "State", "Setup", "HideFromProviderlist", "Icon", "Activate", "Query"}
+	optional = []string{"Shutdown", "Reload", "Refresh", "Health", "Preview", "Complete"}
+
+	packageMain = regexp.MustCompile(`(?m)^package main$`)
+)
+
+var registerTmpl = template.Must(template.New("register").Parse(`// Code generated by internal/builtin/gen.go; DO NOT EDIT.
+
+package {{ .Package }}
+
+import "{{ .Module }}/internal/providers"
+
+func init() {
+	providers.Register(providers.Provider{
+		{{- range .Fields }}
+		{{ . }}: {{ if or (eq . "Name") (eq . "NamePretty") }}&{{ end }}{{ . }},
+		{{- end }}
+	})
+}
+`))
+
+var importsTmpl = template.Must(template.New("imports").Parse(`// Code generated by internal/builtin/gen.go; DO NOT EDIT.
+
+//go:build builtin
+
+package builtin
+
+import (
+	{{- range .Pkgs }}
+	_ "{{ $.Module }}/internal/builtin/{{ . }}"
+	{{- end }}
+)
+`))
+
+func main() {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	pkgs := []string{}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		pkg, err := generate(e.Name())
+		if err != nil {
+			log.Fatalf("%s: %v", e.Name(), err)
+		}
+
+		pkgs = append(pkgs, pkg)
+	}
+
+	var buf bytes.Buffer
+
+	if err := importsTmpl.Execute(&buf, struct {
+		Module string
+		Pkgs   []string
+	}{module, pkgs}); err != nil {
+		log.Fatal(err)
+	}
+
+	write("providers_gen.go", buf.Bytes())
+}
+
+// generate copies a single provider and returns the package name it got.
+func generate(name string) (string, error) {
+	pkg := packageName(name)
+	dir := filepath.Join(src, name)
+
+	if err := os.RemoveAll(pkg); err != nil {
+		return "", err
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		dst := filepath.Join(pkg, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(dst, 0o755)
+		}
+
+		if d.Name() == "makefile" || strings.HasSuffix(path, "_test.go") || filepath.Ext(path) == ".so" {
+			return nil
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if filepath.Ext(path) == ".go" {
+			b = packageMain.ReplaceAll(b, []byte("package "+pkg))
+		}
+
+		return os.WriteFile(dst, b, 0o644)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	symbols, err := exported(dir)
+	if err != nil {
+		return "", err
+	}
+
+	fields := []string{}
+
+	for _, v := range required {
+		if !slices.Contains(symbols, v) {
+			return "", fmt.Errorf("missing symbol %s", v)
+		}
+
+		fields = append(fields, v)
+	}
+
+	for _, v := range optional {
+		if slices.Contains(symbols, v) {
+			fields = append(fields, v)
+		}
+	}
+
+	var buf bytes.Buffer
+
+	if err := registerTmpl.Execute(&buf, struct {
+		Package string
+		Module  string
+		Fields  []string
+	}{pkg, module, fields}); err != nil {
+		return "", err
+	}
+
+	write(filepath.Join(pkg, "register_gen.go"), buf.Bytes())
+
+	return pkg, nil
+}
+
+// exported lists the top-level functions and variables of a provider.
+func exported(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+
+	pkgs, err := parser.ParseDir(fset, dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []string{}
+
+	for _, p := range pkgs {
+		for _, f := range p.Files {
+			for name, obj := range f.Scope.Objects {
+				if obj.Kind == ast.Fun || obj.Kind == ast.Var {
+					res = append(res, name)
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// packageName turns a provider directory into a valid package name, f.e. 1password => p1password.
+func packageName(name string) string {
+	name = strings.ToLower(strings.NewReplacer("-", "", "_", "", ".", "").Replace(name))
+
+	if !token.IsIdentifier(name) {
+		name = "p" + name
+	}
+
+	return name
+}
+
+func write(path string, b []byte) {
+	b, err := format.Source(b)
+	if err != nil {
+		log.Fatalf("%s: %v", path, err)
+	}
+
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
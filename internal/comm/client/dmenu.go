@@ -0,0 +1,126 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// Dmenu reads items from stdin, lets the user pick one through the running frontends and prints its value.
+// Items are lines, or with j json objects with text, subtext, value, icon and preview, as an array or one per line.
+// It exits with 1 if nothing was picked, like dmenu.
+func Dmenu(name, prompt string, j bool, timeout int) {
+	req := pb.DmenuRequest{
+		Name:    name,
+		Prompt:  prompt,
+		Timeout: int32(timeout),
+	}
+
+	items, err := readDmenuItems(os.Stdin, j)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid items: %s\n", err)
+		os.Exit(1)
+	}
+
+	req.Items = items
+
+	b, err := json.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{12})
+	buffer.Write([]byte{1})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		panic(err)
+	}
+
+	if header[0] != 10 {
+		panic("invalid protocol prefix")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		panic(err)
+	}
+
+	resp := &pb.DmenuResponse{}
+	if err := json.Unmarshal(payload, resp); err != nil {
+		panic(err)
+	}
+
+	if !resp.Selected {
+		os.Exit(1)
+	}
+
+	fmt.Println(resp.Value)
+}
+
+func readDmenuItems(r io.Reader, j bool) ([]*pb.DmenuItem, error) {
+	items := []*pb.DmenuItem{}
+
+	if !j {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				items = append(items, &pb.DmenuItem{Text: line})
+			}
+		}
+
+		return items, scanner.Err()
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+		err := json.Unmarshal(content, &items)
+		return items, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+
+	for decoder.More() {
+		item := &pb.DmenuItem{}
+
+		if err := decoder.Decode(item); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// History lists, prunes, clears, exports or imports the history of the running service.
+// Export writes JSON to stdout, import reads the same format from stdin.
+func History(action, provider string) {
+	val, ok := pb.HistoryRequest_Action_value[strings.ToUpper(action)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown action %s\n", action)
+		os.Exit(1)
+	}
+
+	req := pb.HistoryRequest{
+		Provider: provider,
+		Action:   pb.HistoryRequest_Action(val),
+	}
+
+	if req.Action == pb.HistoryRequest_IMPORT {
+		if err := json.NewDecoder(os.Stdin).Decode(&req.Events); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid history: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	b, err := json.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{10})
+	buffer.Write([]byte{1})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		panic(err)
+	}
+
+	if header[0] != 8 {
+		panic("invalid protocol prefix")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		panic(err)
+	}
+
+	resp := &pb.HistoryResponse{}
+	if err := json.Unmarshal(payload, resp); err != nil {
+		panic(err)
+	}
+
+	if !resp.Success {
+		fmt.Fprintln(os.Stderr, resp.Error)
+		os.Exit(1)
+	}
+
+	switch req.Action {
+	case pb.HistoryRequest_LIST:
+		for _, v := range resp.Items {
+			fmt.Printf("%s;%s;%d;%s;%.2f\n", v.Provider, v.Identifier, v.Uses, time.Unix(v.LastUsed, 0).Format(time.DateTime), v.Frecency)
+		}
+	case pb.HistoryRequest_EXPORT:
+		b, err := json.MarshalIndent(resp.Events, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(string(b))
+	default:
+		fmt.Printf("%s: %d entries\n", strings.ToLower(req.Action.String()), resp.Count)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// Incognito reports, enables, disables or toggles the incognito mode of the running service.
+func Incognito(action string) {
+	if action == "" {
+		action = "status"
+	}
+
+	val, ok := pb.IncognitoRequest_Action_value[strings.ToUpper(action)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown action %s\n", action)
+		os.Exit(1)
+	}
+
+	req := pb.IncognitoRequest{
+		Action: pb.IncognitoRequest_Action(val),
+	}
+
+	b, err := json.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{11})
+	buffer.Write([]byte{1})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		panic(err)
+	}
+
+	if header[0] != 9 {
+		panic("invalid protocol prefix")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		panic(err)
+	}
+
+	resp := &pb.IncognitoResponse{}
+	if err := json.Unmarshal(payload, resp); err != nil {
+		panic(err)
+	}
+
+	if resp.Incognito {
+		fmt.Println("incognito")
+	} else {
+		fmt.Println("recording")
+	}
+}
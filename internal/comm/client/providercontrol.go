@@ -0,0 +1,80 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// ProviderControl enables, disables or reloads a provider of the running service.
+func ProviderControl(provider, action string) {
+	val, ok := pb.ProviderControlRequest_Action_value[strings.ToUpper(action)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown action %s\n", action)
+		os.Exit(1)
+	}
+
+	req := pb.ProviderControlRequest{
+		Provider: provider,
+		Action:   pb.ProviderControlRequest_Action(val),
+	}
+
+	b, err := json.Marshal(&req)
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		panic(err)
+	}
+	defer conn.Close()
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{9})
+	buffer.Write([]byte{1})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+	if err != nil {
+		panic(err)
+	}
+
+	reader := bufio.NewReader(conn)
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		panic(err)
+	}
+
+	if header[0] != 7 {
+		panic("invalid protocol prefix")
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint32(header[1:5]))
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		panic(err)
+	}
+
+	resp := &pb.ProviderControlResponse{}
+	if err := json.Unmarshal(payload, resp); err != nil {
+		panic(err)
+	}
+
+	if !resp.Success {
+		fmt.Fprintln(os.Stderr, resp.Error)
+		os.Exit(1)
+	}
+}
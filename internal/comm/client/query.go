@@ -41,6 +41,14 @@ func Query(data string, async, j bool) {
 		Maxresults: int32(maxresults),
 	}
 
+	if len(v) > 3 {
+		req.Exactsearch = v[3] == "true"
+	}
+
+	if len(v) > 4 {
+		req.Matcher = v[4]
+	}
+
 	b, err := json.Marshal(&req)
 	if err != nil {
 		panic(err)
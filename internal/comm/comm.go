@@ -30,6 +30,14 @@ const (
 	SubscribeRequestHandlerPos = 2
 	MenuRequestHandlerPos      = 3
 	StateRequestHandlerPos     = 4
+	PreviewRequestHandlerPos   = 5
+	CompleteRequestHandlerPos  = 6
+	HealthRequestHandlerPos    = 7
+	RefreshRequestHandlerPos   = 8
+	ProviderControlHandlerPos  = 9
+	HistoryRequestHandlerPos   = 10
+	IncognitoRequestHandlerPos = 11
+	DmenuRequestHandlerPos     = 12
 	Protobuf                   = 0
 	JSON                       = 1
 )
@@ -53,6 +61,14 @@ func init() {
 	registry[SubscribeRequestHandlerPos] = &handlers.SubscribeRequest{}
 	registry[MenuRequestHandlerPos] = &handlers.MenuRequest{}
 	registry[StateRequestHandlerPos] = &handlers.StateRequest{}
+	registry[PreviewRequestHandlerPos] = &handlers.PreviewRequest{}
+	registry[CompleteRequestHandlerPos] = &handlers.CompleteRequest{}
+	registry[HealthRequestHandlerPos] = &handlers.HealthRequest{}
+	registry[RefreshRequestHandlerPos] = &handlers.RefreshRequest{}
+	registry[ProviderControlHandlerPos] = &handlers.ProviderControlRequest{}
+	registry[HistoryRequestHandlerPos] = &handlers.HistoryRequest{}
+	registry[IncognitoRequestHandlerPos] = &handlers.IncognitoRequest{}
+	registry[DmenuRequestHandlerPos] = &handlers.DmenuRequest{}
 }
 
 func StartListen() {
@@ -83,7 +99,10 @@ func StartListen() {
 }
 
 func handle(conn net.Conn, cid uint32) {
+	handlers.Connected(cid)
+
 	defer conn.Close()
+	defer handlers.Disconnected(cid)
 
 	for {
 		tb := make([]byte, 1)
@@ -39,7 +39,7 @@ func (a *ActivateRequest) Handle(format uint8, cid uint32, conn net.Conn, data [
 		provider = strings.Split(provider, ":")[0]
 	}
 
-	if p, ok := providers.Providers[provider]; ok {
+	if p, ok := providers.Get(provider); ok {
 		p.Activate(req.Single, req.Identifier, req.Action, req.Query, req.Arguments, format, conn)
 
 		var buffer bytes.Buffer
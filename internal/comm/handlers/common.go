@@ -2,9 +2,53 @@ package handlers
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
 	"net"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	connections   = make(map[uint32]chan struct{})
+	connectionsMu sync.Mutex
 )
 
+// Connected registers a client connection, so handlers waiting on it can notice when it's gone.
+func Connected(cid uint32) {
+	connectionsMu.Lock()
+	connections[cid] = make(chan struct{})
+	connectionsMu.Unlock()
+}
+
+// Disconnected wakes up handlers waiting on the connection.
+func Disconnected(cid uint32) {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	if c, ok := connections[cid]; ok {
+		close(c)
+		delete(connections, cid)
+	}
+}
+
+// closed returns a channel that's closed once the client disconnects.
+func closed(cid uint32) <-chan struct{} {
+	connectionsMu.Lock()
+	defer connectionsMu.Unlock()
+
+	if c, ok := connections[cid]; ok {
+		return c
+	}
+
+	c := make(chan struct{})
+	close(c)
+
+	return c
+}
+
 func writeStatus(status int, conn net.Conn) (bool, error) {
 	var buffer bytes.Buffer
 	buffer.Write([]byte{byte(status)})
@@ -19,3 +63,53 @@ func writeStatus(status int, conn net.Conn) (bool, error) {
 
 	return true, nil
 }
+
+// writeResponse marshals msg in the requested format and writes it with the given response type.
+func writeResponse(format uint8, kind int, msg proto.Message, conn net.Conn) error {
+	var b []byte
+	var err error
+
+	switch format {
+	case 0:
+		b, err = proto.Marshal(msg)
+	case 1:
+		b, err = json.Marshal(msg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{byte(kind)})
+
+	lengthBuf := make([]byte, 4)
+	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
+	buffer.Write(lengthBuf)
+	buffer.Write(b)
+
+	_, err = conn.Write(buffer.Bytes())
+
+	return err
+}
+
+// unmarshal decodes a request in the given format.
+func unmarshal(format uint8, data []byte, req proto.Message) error {
+	switch format {
+	case 0:
+		return proto.Unmarshal(data, req)
+	case 1:
+		return json.Unmarshal(data, req)
+	}
+
+	return nil
+}
+
+// providerName maps sub-providers like "menus:foo" to the provider handling them.
+func providerName(provider string) string {
+	if strings.HasPrefix(provider, "menus:") {
+		return "menus"
+	}
+
+	return provider
+}
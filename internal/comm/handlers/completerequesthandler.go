@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type CompleteRequest struct{}
+
+func (a *CompleteRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.CompleteRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("completerequesthandler", "unmarshal", err)
+		return
+	}
+
+	res := &pb.CompleteResponse{
+		Provider:    req.Provider,
+		Query:       req.Query,
+		Completions: []string{},
+	}
+
+	if p, ok := providers.Get(providerName(req.Provider)); ok && p.Complete != nil {
+		res.Completions = p.Complete(req.Query)
+	}
+
+	if err := writeResponse(format, CompletionResult, res, conn); err != nil {
+		slog.Error("completerequesthandler", "write", err, "provider", req.Provider)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type DmenuRequest struct{}
+
+// Handle registers the items as a temporary menu, asks frontends to open it and waits until the user picks an entry.
+// If the timeout passes or another request with the same name replaces the menu, nothing is selected.
+// If the client disconnects, the menu is removed.
+func (a *DmenuRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.DmenuRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("dmenuhandler", "unmarshal", err)
+		return
+	}
+
+	if req.Name == "" {
+		req.Name = "dmenu"
+	}
+
+	if req.Prompt == "" {
+		req.Prompt = "Dmenu"
+	}
+
+	menu := &common.Menu{
+		Name:                 req.Name,
+		NamePretty:           req.Prompt,
+		HideFromProviderlist: true,
+		FixedOrder:           true,
+	}
+
+	for _, v := range req.Items {
+		e := common.Entry{
+			Text:    v.Text,
+			Subtext: v.Subtext,
+			Value:   v.Value,
+			Icon:    v.Icon,
+			Preview: v.Preview,
+		}
+
+		if e.Value == "" {
+			e.Value = e.Text
+		}
+
+		menu.Entries = append(menu.Entries, e)
+	}
+
+	selected, remove := common.RegisterDmenu(menu)
+	defer remove()
+
+	ProviderUpdated <- fmt.Sprintf("%s:%s", "menus", req.Name)
+
+	var timeout <-chan time.Time
+
+	if req.Timeout > 0 {
+		timeout = time.After(time.Duration(req.Timeout) * time.Second)
+	}
+
+	res := &pb.DmenuResponse{}
+
+	select {
+	case e, ok := <-selected:
+		if ok {
+			res.Selected = true
+			res.Text = e.Text
+			res.Value = e.Value
+		}
+	case <-timeout:
+	case <-closed(cid):
+		slog.Info("dmenuhandler", "disconnected", req.Name)
+		return
+	}
+
+	if err := writeResponse(format, DmenuResult, res, conn); err != nil {
+		slog.Error("dmenuhandler", "write", err, "menu", req.Name)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
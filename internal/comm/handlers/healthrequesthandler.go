@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type HealthRequest struct{}
+
+// Handle reports the health of the requested providers, or of all loaded providers if none are given.
+// Providers without a health hook are considered healthy.
+func (a *HealthRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.HealthRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("healthrequesthandler", "unmarshal", err)
+		return
+	}
+
+	names := req.Providers
+
+	if len(names) == 0 {
+		for _, v := range providers.All() {
+			names = append(names, *v.Name)
+		}
+	}
+
+	res := &pb.HealthResponse{}
+
+	for _, name := range names {
+		status := &pb.HealthResponse_Status{
+			Provider: name,
+			Healthy:  true,
+		}
+
+		p, ok := providers.Get(providerName(name))
+
+		switch {
+		case !ok:
+			status.Healthy = false
+			status.Message = "not loaded"
+		case p.Health != nil:
+			if err := p.Health(); err != nil {
+				status.Healthy = false
+				status.Message = err.Error()
+			}
+		}
+
+		res.Providers = append(res.Providers, status)
+	}
+
+	if err := writeResponse(format, HealthResult, res, conn); err != nil {
+		slog.Error("healthrequesthandler", "write", err, "providers", strings.Join(names, ","))
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
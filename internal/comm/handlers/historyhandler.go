@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common/history"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type HistoryRequest struct{}
+
+// Handle lists, prunes, clears, exports or imports the history of a provider, or of all providers if none is given.
+func (a *HistoryRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.HistoryRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("historyhandler", "unmarshal", err)
+		return
+	}
+
+	res := &pb.HistoryResponse{}
+
+	var count int
+	var err error
+
+	switch req.Action {
+	case pb.HistoryRequest_LIST:
+		var items []history.Item
+
+		items, err = history.List(req.Provider)
+
+		for _, v := range items {
+			res.Items = append(res.Items, &pb.HistoryItem{
+				Provider:   v.Provider,
+				Identifier: v.Identifier,
+				Uses:       int32(v.Uses),
+				LastUsed:   v.LastUsed.Unix(),
+				Frecency:   v.Frecency,
+			})
+		}
+	case pb.HistoryRequest_PRUNE:
+		count, err = history.Prune(req.Provider)
+	case pb.HistoryRequest_CLEAR:
+		count, err = history.Clear(req.Provider)
+	case pb.HistoryRequest_EXPORT:
+		var events []history.Event
+
+		events, err = history.Export(req.Provider)
+
+		for _, v := range events {
+			res.Events = append(res.Events, &pb.HistoryEvent{
+				Provider:   v.Provider,
+				Query:      v.Query,
+				Identifier: v.Identifier,
+				Used:       v.Used.Unix(),
+				App:        v.App,
+				Workspace:  v.Workspace,
+			})
+		}
+	case pb.HistoryRequest_IMPORT:
+		events := []history.Event{}
+
+		for _, v := range req.Events {
+			if req.Provider != "" && v.Provider != req.Provider {
+				continue
+			}
+
+			events = append(events, history.Event{
+				Provider:   v.Provider,
+				Query:      v.Query,
+				Identifier: v.Identifier,
+				Used:       time.Unix(v.Used, 0),
+				App:        v.App,
+				Workspace:  v.Workspace,
+			})
+		}
+
+		count, err = history.Import(events)
+	}
+
+	res.Success = err == nil
+	res.Count = int32(count)
+
+	if err != nil {
+		slog.Error("historyhandler", req.Action.String(), err)
+		res.Error = err.Error()
+	}
+
+	if err := writeResponse(format, HistoryResult, res, conn); err != nil {
+		slog.Error("historyhandler", "write", err, "provider", req.Provider)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type IncognitoRequest struct{}
+
+// Handle reports or changes the incognito mode, which suspends recording history.
+func (a *IncognitoRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.IncognitoRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("incognitohandler", "unmarshal", err)
+		return
+	}
+
+	current := common.Incognito()
+
+	switch req.Action {
+	case pb.IncognitoRequest_ENABLE:
+		common.SetIncognito(true)
+	case pb.IncognitoRequest_DISABLE:
+		common.SetIncognito(false)
+	case pb.IncognitoRequest_TOGGLE:
+		common.SetIncognito(!current)
+	}
+
+	if common.Incognito() != current {
+		ProviderUpdated <- "providerlist"
+	}
+
+	res := &pb.IncognitoResponse{
+		Incognito: common.Incognito(),
+	}
+
+	if err := writeResponse(format, IncognitoResult, res, conn); err != nil {
+		slog.Error("incognitohandler", "write", err)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type PreviewRequest struct{}
+
+func (a *PreviewRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.PreviewRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("previewrequesthandler", "unmarshal", err)
+		return
+	}
+
+	res := &pb.PreviewResponse{
+		Provider:   req.Provider,
+		Identifier: req.Identifier,
+	}
+
+	if p, ok := providers.Get(providerName(req.Provider)); ok && p.Preview != nil {
+		res.Preview, res.PreviewType = p.Preview(req.Identifier)
+	}
+
+	if err := writeResponse(format, PreviewResult, res, conn); err != nil {
+		slog.Error("previewrequesthandler", "write", err, "provider", req.Provider)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type ProviderControlRequest struct{}
+
+// Handle enables, disables or reloads a provider at runtime.
+func (a *ProviderControlRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.ProviderControlRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("providercontrolhandler", "unmarshal", err)
+		return
+	}
+
+	var err error
+
+	switch req.Action {
+	case pb.ProviderControlRequest_ENABLE:
+		err = providers.Enable(req.Provider)
+	case pb.ProviderControlRequest_DISABLE:
+		err = providers.Disable(req.Provider)
+	case pb.ProviderControlRequest_RELOAD:
+		err = providers.Reload(req.Provider)
+	}
+
+	res := &pb.ProviderControlResponse{
+		Provider: req.Provider,
+		Success:  err == nil,
+	}
+
+	if err != nil {
+		slog.Error("providercontrolhandler", req.Action.String(), err)
+		res.Error = err.Error()
+	} else {
+		ProviderUpdated <- "providerlist"
+	}
+
+	if err := writeResponse(format, ProviderControlled, res, conn); err != nil {
+		slog.Error("providercontrolhandler", "write", err, "provider", req.Provider)
+		return
+	}
+
+	writeStatus(StatusDone, conn)
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/common"
 	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
@@ -27,6 +28,13 @@ const (
 	QueryAsyncItem     = 1
 	ActivationFinished = 2
 	ProviderState      = 3
+	PreviewResult      = 4
+	CompletionResult   = 5
+	HealthResult       = 6
+	ProviderControlled = 7
+	HistoryResult      = 8
+	IncognitoResult    = 9
+	DmenuResult        = 10
 )
 
 var (
@@ -98,6 +106,17 @@ func (h *QueryRequest) Handle(format uint8, cid uint32, conn net.Conn, data []by
 		}
 	}
 
+	matcher := common.Matcher(req.Matcher)
+
+	if !matcher.Valid() {
+		slog.Warn("queryhandler", "matcher", "unknown matcher, using the provider's", "matcher", matcher)
+		matcher = common.MatcherDefault
+	}
+
+	if matcher == common.MatcherDefault && req.Exactsearch {
+		matcher = common.MatcherExact
+	}
+
 	wsprefix := ""
 
 	if slices.Contains(req.Providers, "websearch") {
@@ -150,8 +169,8 @@ func (h *QueryRequest) Handle(format uint8, cid uint32, conn net.Conn, data []by
 
 		go func(text string, wg *sync.WaitGroup) {
 			defer wg.Done()
-			if p, ok := providers.Providers[v]; ok {
-				res := p.Query(conn, text, len(req.Providers) == 1, req.Exactsearch, format)
+			if p, ok := providers.Get(v); ok {
+				res := p.Query(conn, text, len(req.Providers) == 1, matcher, format)
 
 				mut.Lock()
 				entries = append(entries, res...)
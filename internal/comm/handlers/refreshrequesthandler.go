@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+type RefreshRequest struct{}
+
+// Handle asks the provider to refresh its data and notifies subscribers afterwards.
+func (a *RefreshRequest) Handle(format uint8, cid uint32, conn net.Conn, data []byte) {
+	req := &pb.RefreshRequest{}
+
+	if err := unmarshal(format, data, req); err != nil {
+		slog.Error("refreshrequesthandler", "unmarshal", err)
+		return
+	}
+
+	if p, ok := providers.Get(providerName(req.Provider)); ok && p.Refresh != nil {
+		p.Refresh()
+		ProviderUpdated <- req.Provider
+	}
+
+	writeStatus(StatusDone, conn)
+}
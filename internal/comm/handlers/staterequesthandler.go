@@ -39,7 +39,12 @@ func (a *StateRequest) Handle(format uint8, cid uint32, conn net.Conn, data []by
 		p = "menus"
 	}
 
-	res := providers.Providers[p].State(req.Provider)
+	res := &pb.ProviderStateResponse{}
+
+	if provider, ok := providers.Get(p); ok {
+		res = provider.State(req.Provider)
+	}
+
 	res.Provider = req.Provider
 
 	var b []byte
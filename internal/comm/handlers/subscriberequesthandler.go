@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/json"
+	"fmt"
 	"log/slog"
 	"net"
 	"slices"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/common"
 	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
 	"google.golang.org/protobuf/proto"
 )
@@ -50,7 +52,10 @@ var (
 
 const (
 	SubscriptionDataChanged = 0
-	SubscriptionHealthCheck = 230
+	// SubscriptionEntriesChanged tells subscribers to query again, f.e. after a menu re-created its entries in the background.
+	// Unlike SubscriptionDataChanged, it doesn't ask frontends to open or navigate to anything.
+	SubscriptionEntriesChanged = 1
+	SubscriptionHealthCheck    = 230
 )
 
 type sub struct {
@@ -70,6 +75,32 @@ func init() {
 
 	// go checkHealth()
 
+	// providers becoming available change the providerlist
+	go func() {
+		for range providers.Changed {
+			ProviderUpdated <- "providerlist"
+		}
+	}()
+
+	// menus that re-created their entries, only subscribers of all menus or of that menu are told.
+	go func() {
+		for m := range common.MenuEntriesChanged {
+			value := fmt.Sprintf("menus:%s", m)
+
+			mut.Lock()
+
+			for k, v := range subs {
+				if (v.provider == "menus" || v.provider == value) && v.interval == 0 && v.query == "" {
+					if ok := send(SubscriptionEntriesChanged, v.format, v.conn, value); !ok {
+						delete(subs, k)
+					}
+				}
+			}
+
+			mut.Unlock()
+		}
+	}()
+
 	// handle general realtime subs
 	go func() {
 		for p := range ProviderUpdated {
@@ -125,7 +156,10 @@ func subscribe(format uint8, interval int, provider, query string, conn net.Conn
 }
 
 func watch(format uint8, s *sub, conn net.Conn) {
-	p := providers.Providers[s.provider]
+	p, ok := providers.Get(s.provider)
+	if !ok {
+		return
+	}
 
 	for {
 		time.Sleep(time.Duration(s.interval) * time.Millisecond)
@@ -134,7 +168,7 @@ func watch(format uint8, s *sub, conn net.Conn) {
 			return
 		}
 
-		res := p.Query(conn, s.query, true, false, format)
+		res := p.Query(conn, s.query, true, common.MatcherDefault, format)
 
 		slices.SortFunc(res, sortEntries)
 
@@ -169,6 +203,10 @@ func watch(format uint8, s *sub, conn net.Conn) {
 }
 
 func updated(format uint8, conn net.Conn, value string) bool {
+	return send(SubscriptionDataChanged, format, conn, value)
+}
+
+func send(kind byte, format uint8, conn net.Conn, value string) bool {
 	resp := pb.SubscribeResponse{
 		Value: value,
 	}
@@ -188,7 +226,7 @@ func updated(format uint8, conn net.Conn, value string) bool {
 	}
 
 	var buffer bytes.Buffer
-	buffer.Write([]byte{SubscriptionDataChanged})
+	buffer.Write([]byte{kind})
 
 	lengthBuf := make([]byte, 4)
 	binary.BigEndian.PutUint32(lengthBuf, uint32(len(b)))
@@ -19,6 +19,7 @@ import (
 var (
 	Name        = "1password"
 	NamePretty  = "1Password"
+	ABIVersion  = common.ProviderABIVersion
 	config      *Config
 	cachedItems []OpItem
 )
@@ -165,7 +166,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
@@ -187,7 +190,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 		}
 
 		if query != "" {
-			score, positions, start := common.FuzzyScore(query, v.Title, exact)
+			score, positions, start := common.MatchScore(query, v.Title, matcher)
 
 			e.Score = score
 			e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
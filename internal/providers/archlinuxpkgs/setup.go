@@ -13,6 +13,8 @@ import (
 	"runtime/debug"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/v2/internal/util"
@@ -24,11 +26,15 @@ import (
 var (
 	Name          = "archlinuxpkgs"
 	NamePretty    = "Arch Linux Packages"
-	config        *Config
+	ABIVersion    = common.ProviderABIVersion
+	current       atomic.Pointer[Config]
 	installed     = []string{}
 	installedOnly = false
 	cacheFile     = common.CacheFile("archlinuxpkgs.json")
 	cachedData    = newCachedData()
+	corpus        *common.Corpus
+	corpusKeys    []string
+	corpusMut     sync.Mutex
 )
 
 //go:embed README.md
@@ -113,9 +119,20 @@ func clearCache() {
 }
 
 func Setup() {
+	loadConfig()
+
+	setup()
+	go clearCache()
+}
+
+func getConfig() *Config {
+	return current.Load()
+}
+
+func loadConfig() {
 	helper := detectHelper()
 
-	config = &Config{
+	config := &Config{
 		Config: common.Config{
 			Icon:     "applications-internet",
 			MinScore: 20,
@@ -131,8 +148,13 @@ func Setup() {
 		NamePretty = config.NamePretty
 	}
 
-	setup()
-	go clearCache()
+	current.Store(config)
+}
+
+// Reload swaps in the new install and remove commands. The package index
+// isn't rebuilt, use the refresh action for that.
+func Reload() {
+	loadConfig()
 }
 
 func setup() {
@@ -154,6 +176,12 @@ func setup() {
 
 func freeMem() {
 	cachedData = newCachedData()
+
+	corpusMut.Lock()
+	corpus = nil
+	corpusKeys = nil
+	corpusMut.Unlock()
+
 	debug.FreeOSMemory()
 }
 
@@ -168,6 +196,8 @@ func PrintDoc() {
 }
 
 func Activate(single bool, identifier, action string, query string, args string, format uint8, conn net.Conn) {
+	config := getConfig()
+
 	defer freeMem()
 
 	switch action {
@@ -209,7 +239,7 @@ func Activate(single bool, identifier, action string, query string, args string,
 		return
 	}
 
-	pkgcmd = strings.ReplaceAll(pkgcmd, "%VALUE%", name)
+	pkgcmd = common.Expand(pkgcmd, map[string]string{"VALUE": name})
 	toRun := common.WrapWithTerminal(pkgcmd)
 
 	if !config.AutoWrapWithTerminal {
@@ -227,7 +257,11 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	config := getConfig()
+
+	matcher = matcher.Or(config.Matcher)
+
 	cacheChan <- struct{}{}
 
 	entries := []*pb.QueryResponse_Item{}
@@ -241,73 +275,109 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 		}
 	}
 
-	for k, v := range cachedData.Packages {
-		score, positions, s := common.FuzzyScore(query, v.Name, exact)
+	if query != "" {
+		c, keys := getCorpus()
 
-		score2, positions2, s2 := common.FuzzyScore(query, v.Description, exact)
+		for _, res := range c.Score(query, matcher, config.MinScore, 0) {
+			k := keys[res.Index]
+			v := cachedData.Packages[k]
 
-		if score2 > score {
-			score = score2 / 2
-			positions = positions2
-			s = s2
-		}
+			score := res.Score
 
-		if (score > config.MinScore || query == "") && (!installedOnly || (installedOnly && v.Installed)) {
-			state := []string{}
-			a := []string{}
-
-			if v.Installed {
-				state = append(state, "installed")
-				a = append(a, ActionRemove)
-			} else {
-				state = append(state, "available")
-				a = append(a, ActionInstall)
+			// description matches are worth less than name matches
+			if res.Field == 1 {
+				score = score / 2
 			}
 
-			if v.URL != "" {
-				a = append(a, "visit_url")
+			if score > config.MinScore && (!installedOnly || v.Installed) {
+				entries = append(entries, packageToEntry(k, v, score, res.Positions, res.Start))
 			}
+		}
 
-			subtext := fmt.Sprintf("[%s]", strings.ToLower(v.Repository))
-			if v.Installed {
-				subtext = fmt.Sprintf("[%s] [installed]", strings.ToLower(v.Repository))
-			}
+		return entries
+	}
 
-			entries = append(entries, &pb.QueryResponse_Item{
-				Identifier:  k,
-				Text:        v.Name,
-				Type:        pb.QueryResponse_REGULAR,
-				Subtext:     subtext,
-				Provider:    Name,
-				State:       state,
-				Actions:     a,
-				Score:       score,
-				Preview:     v.FullInfo,
-				PreviewType: util.PreviewTypeText,
-				Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
-					Start:     s,
-					Field:     "text",
-					Positions: positions,
-				},
-			})
+	for k, v := range cachedData.Packages {
+		if !installedOnly || v.Installed {
+			entries = append(entries, packageToEntry(k, v, 0, nil, 0))
 		}
 	}
 
-	if query == "" {
-		slices.SortFunc(entries, func(a, b *pb.QueryResponse_Item) int {
-			return strings.Compare(a.Text, b.Text)
-		})
-	}
+	slices.SortFunc(entries, func(a, b *pb.QueryResponse_Item) int {
+		return strings.Compare(a.Text, b.Text)
+	})
 
 	return entries
 }
 
+// getCorpus returns the corpus of all packages and their keys, building it if the cache was cleared.
+func getCorpus() (*common.Corpus, []string) {
+	corpusMut.Lock()
+	defer corpusMut.Unlock()
+
+	if corpus == nil {
+		buildCorpus()
+	}
+
+	return corpus, corpusKeys
+}
+
+func buildCorpus() {
+	corpus = common.NewCorpus(len(cachedData.Packages))
+	corpusKeys = make([]string, 0, len(cachedData.Packages))
+
+	for k, v := range cachedData.Packages {
+		corpus.Add(v.Name, v.Description)
+		corpusKeys = append(corpusKeys, k)
+	}
+}
+
+func packageToEntry(k string, v Package, score int32, positions []int32, start int32) *pb.QueryResponse_Item {
+	state := []string{}
+	a := []string{}
+
+	if v.Installed {
+		state = append(state, "installed")
+		a = append(a, ActionRemove)
+	} else {
+		state = append(state, "available")
+		a = append(a, ActionInstall)
+	}
+
+	if v.URL != "" {
+		a = append(a, "visit_url")
+	}
+
+	subtext := fmt.Sprintf("[%s]", strings.ToLower(v.Repository))
+	if v.Installed {
+		subtext = fmt.Sprintf("[%s] [installed]", strings.ToLower(v.Repository))
+	}
+
+	return &pb.QueryResponse_Item{
+		Identifier:  k,
+		Text:        v.Name,
+		Type:        pb.QueryResponse_REGULAR,
+		Subtext:     subtext,
+		Provider:    Name,
+		State:       state,
+		Actions:     a,
+		Score:       score,
+		Preview:     v.FullInfo,
+		PreviewType: util.PreviewTypeText,
+		Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
+			Start:     start,
+			Field:     "text",
+			Positions: positions,
+		},
+	}
+}
+
 func Icon() string {
-	return config.Icon
+	return getConfig().Icon
 }
 
 func HideFromProviderlist() bool {
-	return config.HideFromProviderlist
+	return getConfig().HideFromProviderlist
 }
 
 func State(provider string) *pb.ProviderStateResponse {
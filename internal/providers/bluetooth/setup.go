@@ -20,6 +20,7 @@ import (
 var (
 	Name       = "bluetooth"
 	NamePretty = "Bluetooth"
+	ABIVersion = common.ProviderABIVersion
 	find       = false
 )
 
@@ -203,7 +204,9 @@ quit
 	}
 }
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
@@ -247,7 +250,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 		}
 
 		if query != "" {
-			score, pos, start := common.FuzzyScore(query, v.Name, exact)
+			score, pos, start := common.MatchScore(query, v.Name, matcher)
 
 			e.Score = score
 			e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
@@ -23,6 +23,7 @@ import (
 var (
 	Name              = "bookmarks"
 	NamePretty        = "Bookmarks"
+	ABIVersion        = common.ProviderABIVersion
 	config            *Config
 	bookmarks         = []Bookmark{}
 	availableBrowsers = make(map[string]string)
@@ -392,8 +393,8 @@ func Activate(single bool, identifier, action string, query string, args string,
 			}
 		}
 
-		if strings.Contains(command, "%VALUE%") {
-			command = strings.ReplaceAll(command, "%VALUE%", bookmarks[i].URL)
+		if common.UsesPlaceholder(command, "VALUE") {
+			command = common.Expand(command, map[string]string{"VALUE": bookmarks[i].URL})
 		} else {
 			command = fmt.Sprintf("%s %s", command, bookmarks[i].URL)
 		}
@@ -617,7 +618,9 @@ func importBrowserBookmarks() {
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	if isGit && config.r == nil {
 		common.SetupGit(Name, config)
 		loadBookmarks()
@@ -652,7 +655,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 			}
 
 			if query != "" {
-				_, e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start, _ = calcScore(query, b, exact)
+				_, e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start, _ = calcScore(query, b, matcher)
 			}
 
 			if e.Score > highestScore {
@@ -757,7 +760,7 @@ func State(provider string) *pb.ProviderStateResponse {
 	}
 }
 
-func calcScore(q string, d Bookmark, exact bool) (string, int32, []int32, int32, bool) {
+func calcScore(q string, d Bookmark, matcher common.Matcher) (string, int32, []int32, int32, bool) {
 	var scoreRes int32
 	var posRes []int32
 	var startRes int32
@@ -767,7 +770,7 @@ func calcScore(q string, d Bookmark, exact bool) (string, int32, []int32, int32,
 	toSearch := []string{d.Description, d.URL, d.Category}
 
 	for k, v := range toSearch {
-		score, pos, start := common.FuzzyScore(q, v, exact)
+		score, pos, start := common.MatchScore(q, v, matcher)
 
 		if score > scoreRes {
 			scoreRes = score
@@ -27,6 +27,7 @@ import (
 var (
 	Name       = "calc"
 	NamePretty = "Calculator/Unit-Conversion"
+	ABIVersion = common.ProviderABIVersion
 	config     *Config
 )
 
@@ -171,6 +172,10 @@ func Activate(single bool, identifier, action string, query string, args string,
 }
 
 func saveToHistory(query, result string) {
+	if !common.ShouldRecord(Name, query) {
+		return
+	}
+
 	md5 := md5.Sum([]byte(query))
 	md5str := hex.EncodeToString(md5[:])
 
@@ -185,7 +190,7 @@ func saveToHistory(query, result string) {
 	saveHist()
 }
 
-func Query(conn net.Conn, query string, single bool, _ bool, format uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, _ common.Matcher, format uint8) []*pb.QueryResponse_Item {
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -32,9 +33,10 @@ import (
 var (
 	Name             = "clipboard"
 	NamePretty       = "Clipboard"
+	ABIVersion       = common.ProviderABIVersion
 	file             = common.CacheFile("clipboard.gob")
 	imgTypes         = make(map[string]string)
-	config           *Config
+	current          atomic.Pointer[Config]
 	clipboardhistory = make(map[string]*Item)
 	mu               sync.Mutex
 	currentMode      = Combined
@@ -77,7 +79,35 @@ type Config struct {
 func Setup() {
 	start := time.Now()
 
-	config = &Config{
+	loadConfig()
+
+	imgTypes["image/png"] = "png"
+	imgTypes["image/jpg"] = "jpg"
+	imgTypes["image/jpeg"] = "jpeg"
+	imgTypes["image/webm"] = "webm"
+
+	loadFromFile()
+
+	go handleChange()
+	go handleSaveToFile()
+
+	if getConfig().IgnoreSymbols {
+		setupUnicodeSymbols()
+	}
+
+	if getConfig().AutoCleanup != 0 {
+		go cleanup()
+	}
+
+	slog.Info(Name, "history", len(clipboardhistory), "time", time.Since(start))
+}
+
+func getConfig() *Config {
+	return current.Load()
+}
+
+func loadConfig() {
+	config := &Config{
 		Config: common.Config{
 			Icon:     "user-bookmarks",
 			MinScore: 30,
@@ -96,25 +126,13 @@ func Setup() {
 		NamePretty = config.NamePretty
 	}
 
-	imgTypes["image/png"] = "png"
-	imgTypes["image/jpg"] = "jpg"
-	imgTypes["image/jpeg"] = "jpeg"
-	imgTypes["image/webm"] = "webm"
-
-	loadFromFile()
-
-	go handleChange()
-	go handleSaveToFile()
-
-	if config.IgnoreSymbols {
-		setupUnicodeSymbols()
-	}
-
-	if config.AutoCleanup != 0 {
-		go cleanup()
-	}
+	current.Store(config)
+}
 
-	slog.Info(Name, "history", len(clipboardhistory), "time", time.Since(start))
+// Reload swaps in the new config. Turning on ignore_symbols or auto_cleanup
+// only takes effect after a restart, turning auto_cleanup off stops it.
+func Reload() {
+	loadConfig()
 }
 
 func Available() bool {
@@ -135,6 +153,11 @@ func Available() bool {
 
 func cleanup() {
 	for {
+		config := getConfig()
+		if config.AutoCleanup == 0 {
+			return
+		}
+
 		time.Sleep(time.Duration(config.AutoCleanup) * time.Minute)
 
 		i := 0
@@ -259,6 +282,8 @@ func cleanupImages() {
 }
 
 func saveToFile() {
+	config := getConfig()
+
 	if len(clipboardhistory) > config.MaxItems {
 		trim()
 	}
@@ -298,7 +323,7 @@ func handleChange() {
 	scanner := bufio.NewScanner(stdout)
 
 	for scanner.Scan() {
-		if paused {
+		if paused || common.Incognito() {
 			continue
 		}
 
@@ -404,7 +429,9 @@ func updateImage(out []byte) {
 }
 
 func updateText(text string) {
-	if strings.TrimSpace(text) == "" {
+	config := getConfig()
+
+	if strings.TrimSpace(text) == "" || !common.ShouldRecord(Name, text) {
 		return
 	}
 
@@ -515,6 +542,8 @@ const (
 )
 
 func Activate(single bool, identifier, action string, query string, args string, format uint8, conn net.Conn) {
+	config := getConfig()
+
 	if action == "" {
 		action = ActionCopy
 	}
@@ -545,7 +574,7 @@ func Activate(single bool, identifier, action string, query string, args string,
 				return
 			}
 
-			toRun := strings.ReplaceAll(config.ImageEditorCmd, "%FILE%", item.Img)
+			toRun := common.Expand(config.ImageEditorCmd, map[string]string{"FILE": item.Img})
 
 			cmd := exec.Command("sh", "-c", toRun)
 
@@ -573,7 +602,7 @@ func Activate(single bool, identifier, action string, query string, args string,
 		var run string
 
 		if config.TextEditorCmd != "" {
-			run = strings.ReplaceAll(config.TextEditorCmd, "%FILE%", tmpFile.Name())
+			run = common.Expand(config.TextEditorCmd, map[string]string{"FILE": tmpFile.Name()})
 		} else {
 			run = fmt.Sprintf("xdg-open file://%s", tmpFile.Name())
 
@@ -641,7 +670,11 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	config := getConfig()
+
+	matcher = matcher.Or(config.Matcher)
+
 	entries := []*pb.QueryResponse_Item{}
 
 	for k, v := range clipboardhistory {
@@ -674,7 +707,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 		}
 
 		if query != "" {
-			score, pos, start := common.FuzzyScore(query, v.Content, exact)
+			score, pos, start := common.MatchScore(query, v.Content, matcher)
 
 			e.Score = score
 			e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
@@ -721,11 +754,11 @@ func getMimetypes() []string {
 }
 
 func Icon() string {
-	return config.Icon
+	return getConfig().Icon
 }
 
 func HideFromProviderlist() bool {
-	return config.HideFromProviderlist
+	return getConfig().HideFromProviderlist
 }
 
 func State(provider string) *pb.ProviderStateResponse {
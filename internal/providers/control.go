@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Changed receives the name of providers that became available after loading.
+var Changed = make(chan string)
+
+// disabled holds providers that were disabled at runtime, so they can be enabled again without reloading them.
+var disabled map[string]Provider
+
+// Get returns the provider with the given name, if it is loaded and enabled.
+func Get(name string) (Provider, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	p, ok := Providers[name]
+
+	return p, ok
+}
+
+// All returns all loaded and enabled providers, sorted by name.
+func All() []Provider {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	res := make([]Provider, 0, len(Providers))
+
+	for _, v := range Providers {
+		res = append(res, v)
+	}
+
+	slices.SortFunc(res, func(a, b Provider) int {
+		switch {
+		case *a.Name < *b.Name:
+			return -1
+		case *a.Name > *b.Name:
+			return 1
+		}
+
+		return 0
+	})
+
+	return res
+}
+
+// Disable removes a provider until it is enabled again. Providers with a Shutdown hook are shut down, the others keep running in the background, but won't be queried anymore.
+func Disable(name string) error {
+	mu.Lock()
+
+	p, ok := Providers[name]
+	if !ok {
+		mu.Unlock()
+		return fmt.Errorf("provider %s is not enabled", name)
+	}
+
+	delete(Providers, name)
+
+	// a provider that was shut down can't be reused, it is loaded and set up again when enabled.
+	if p.Shutdown == nil {
+		disabled[name] = p
+	} else if path, ok := paths[name]; ok {
+		ignoredPaths[name] = path
+	}
+
+	mu.Unlock()
+
+	if p.Shutdown != nil {
+		p.Shutdown()
+	}
+
+	slog.Info("providers", "disabled", name)
+
+	return nil
+}
+
+// Enable adds a provider that was disabled at runtime or ignored via IgnoredProviders.
+// Loading and checking availability can be slow, so it happens without holding the lock.
+func Enable(name string) error {
+	mu.Lock()
+
+	if _, ok := Providers[name]; ok {
+		mu.Unlock()
+		return fmt.Errorf("provider %s is already enabled", name)
+	}
+
+	if p, ok := disabled[name]; ok {
+		delete(disabled, name)
+		Providers[name] = p
+		mu.Unlock()
+
+		slog.Info("providers", "enabled", name)
+
+		return nil
+	}
+
+	if _, ok := unavailable[name]; ok {
+		mu.Unlock()
+		return fmt.Errorf("provider %s is not available", name)
+	}
+
+	p, isBuiltin := builtin[name]
+	path, isIgnored := ignoredPaths[name]
+
+	mu.Unlock()
+
+	if !isBuiltin {
+		if !isIgnored {
+			return fmt.Errorf("provider %s is not installed", name)
+		}
+
+		var err error
+
+		p, err = loadPath(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !p.Available() {
+		if p.Shutdown != nil {
+			p.Shutdown()
+		}
+
+		return fmt.Errorf("provider %s is not available", name)
+	}
+
+	mu.Lock()
+
+	if _, ok := Providers[name]; ok {
+		mu.Unlock()
+
+		if p.Shutdown != nil {
+			p.Shutdown()
+		}
+
+		return fmt.Errorf("provider %s is already enabled", name)
+	}
+
+	delete(ignoredPaths, name)
+	Providers[*p.Name] = start(p)
+
+	mu.Unlock()
+
+	slog.Info("providers", "enabled", name)
+
+	return nil
+}
+
+// Reload re-reads the config of a provider through its Reload hook. Setup isn't used for this, as it isn't safe to run twice.
+func Reload(name string) error {
+	p, ok := Get(name)
+	if !ok {
+		return fmt.Errorf("provider %s is not enabled", name)
+	}
+
+	if p.Reload == nil {
+		return fmt.Errorf("provider %s doesn't support reloading", name)
+	}
+
+	p.Reload()
+
+	slog.Info("providers", "reloaded", name)
+
+	return nil
+}
+
+// recheck periodically registers and sets up providers that became available since loading.
+func recheck(interval time.Duration) {
+	for {
+		time.Sleep(interval)
+
+		mu.RLock()
+		candidates := make(map[string]Provider, len(unavailable))
+		for k, v := range unavailable {
+			candidates[k] = v
+		}
+		mu.RUnlock()
+
+		if len(candidates) == 0 {
+			return
+		}
+
+		// Available can be slow, so it is checked without holding the lock.
+		for name, p := range candidates {
+			if !p.Available() {
+				continue
+			}
+
+			mu.Lock()
+			delete(unavailable, name)
+			Providers[name] = start(p)
+			mu.Unlock()
+
+			slog.Info("providers", "available", name)
+
+			Changed <- name
+		}
+	}
+}
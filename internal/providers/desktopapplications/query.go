@@ -16,7 +16,9 @@ import (
 
 var desktop = os.Getenv("XDG_CURRENT_DESKTOP")
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 	entries := make([]*pb.QueryResponse_Item, 0, len(files)*2) // Estimate for entries + action
 
@@ -60,7 +62,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 		subtext := v.GenericName
 
 		if query != "" {
-			match, score, positions, fs, ok = calcScore(query, &v.Data, exact)
+			match, score, positions, fs, ok = calcScore(query, &v.Data, matcher)
 
 			if ok && match != v.Name {
 				subtext = match
@@ -184,7 +186,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 				subtext := v.Name
 
 				if query != "" {
-					match, score, positions, fs, ok = calcScore(query, &a, exact)
+					match, score, positions, fs, ok = calcScore(query, &a, matcher)
 
 					if ok && match != a.Name {
 						subtext = match
@@ -276,7 +278,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 	return entries
 }
 
-func calcScore(q string, d *Data, exact bool) (string, int32, []int32, int32, bool) {
+func calcScore(q string, d *Data, matcher common.Matcher) (string, int32, []int32, int32, bool) {
 	var scoreRes int32
 	var posRes []int32
 	var startRes int32
@@ -289,7 +291,7 @@ func calcScore(q string, d *Data, exact bool) (string, int32, []int32, int32, bo
 	}
 
 	for k, v := range toSearch {
-		score, pos, start := common.FuzzyScore(q, v, exact)
+		score, pos, start := common.MatchScore(q, v, matcher)
 
 		if score > scoreRes {
 			scoreRes = score
@@ -26,6 +26,7 @@ type DesktopFile struct {
 var (
 	Name       = "desktopapplications"
 	NamePretty = "Desktop Applications"
+	ABIVersion = common.ProviderABIVersion
 	h          = history.Load(Name)
 	pins       = loadpinned()
 	pinsMu     sync.RWMutex
@@ -0,0 +1,593 @@
+package providers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// ExternalExt is the file extension of executables that provide a provider over JSON-RPC 2.0 via stdio.
+const ExternalExt = ".provider"
+
+const (
+	externalCallTimeout = 10 * time.Second
+	// setup, activate, reload and refresh may do more work than answering a query.
+	externalLongCallTimeout = 2 * time.Minute
+	externalMaxBackoff      = 30 * time.Second
+	externalMaxMessage      = 16 * 1024 * 1024
+	// the process is killed and restarted by the supervisor after this many calls in a row timed out.
+	externalMaxTimeouts = 3
+)
+
+var errExternalExited = errors.New("provider process exited")
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+type externalInfo struct {
+	Name                 string   `json:"name"`
+	NamePretty           string   `json:"name_pretty"`
+	Icon                 string   `json:"icon"`
+	HideFromProviderlist bool     `json:"hide_from_providerlist"`
+	Available            *bool    `json:"available"`
+	Doc                  string   `json:"doc"`
+	Capabilities         []string `json:"capabilities"`
+}
+
+type externalQuery struct {
+	Query   string         `json:"query"`
+	Single  bool           `json:"single"`
+	Matcher common.Matcher `json:"matcher"`
+}
+
+type externalActivate struct {
+	Single     bool   `json:"single"`
+	Identifier string `json:"identifier"`
+	Action     string `json:"action"`
+	Query      string `json:"query"`
+	Args       string `json:"args"`
+}
+
+type externalIdentifier struct {
+	Identifier string `json:"identifier"`
+}
+
+type externalPreview struct {
+	Preview     string `json:"preview"`
+	PreviewType string `json:"preview_type"`
+}
+
+type externalState struct {
+	Provider string `json:"provider"`
+}
+
+// externalProvider is a provider running as a separate, supervised process.
+type externalProvider struct {
+	path string
+
+	// set once after the first initialize, the provider can't change them.
+	name       string
+	namePretty string
+
+	id       atomic.Uint64
+	setup    atomic.Bool
+	stopped  atomic.Bool
+	timeouts atomic.Int32
+	writeMu  sync.Mutex
+
+	// mu guards everything below, info is updated whenever the process is initialized again.
+	mu      sync.Mutex
+	info    externalInfo
+	running bool
+	cmd     *exec.Cmd
+	done    chan struct{}
+	stdin   io.WriteCloser
+	pending map[uint64]chan rpcResponse
+}
+
+func loadExternal(path string) (Provider, error) {
+	p := &externalProvider{
+		path:    path,
+		pending: make(map[uint64]chan rpcResponse),
+	}
+
+	done, err := p.start()
+	if err != nil {
+		return Provider{}, err
+	}
+
+	if err := p.initialize(); err != nil {
+		p.kill(done)
+		return Provider{}, err
+	}
+
+	info := p.getInfo()
+	p.name = info.Name
+	p.namePretty = info.NamePretty
+
+	go p.supervise(done)
+
+	provider := Provider{
+		Name:                 &p.name,
+		NamePretty:           &p.namePretty,
+		Available:            p.available,
+		PrintDoc:             p.printDoc,
+		State:                p.state,
+		Setup:                p.runSetup,
+		HideFromProviderlist: p.hideFromProviderlist,
+		Icon:                 p.icon,
+		Activate:             p.activate,
+		Query:                p.query,
+		Shutdown:             p.stop,
+	}
+
+	for _, v := range info.Capabilities {
+		switch v {
+		case "shutdown":
+		case "reload":
+			provider.Reload = p.notify("reload")
+		case "refresh":
+			provider.Refresh = p.notify("refresh")
+		case "health":
+			provider.Health = p.health
+		case "preview":
+			provider.Preview = p.preview
+		case "complete":
+			provider.Complete = p.complete
+		default:
+			slog.Error("providers", "external", p.path, "capability", "unknown", "name", v)
+		}
+	}
+
+	return provider, nil
+}
+
+// start spawns the process. The returned channel is closed once the process exited.
+func (p *externalProvider) start() (chan struct{}, error) {
+	cmd := exec.Command(p.path)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Pdeathsig: syscall.SIGTERM,
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+
+	p.mu.Lock()
+	p.cmd = cmd
+	p.stdin = stdin
+	p.done = done
+	p.running = true
+	p.mu.Unlock()
+
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+
+		for scanner.Scan() {
+			slog.Info("providers", "external", p.path, "stderr", scanner.Text())
+		}
+	}()
+
+	go func() {
+		defer close(done)
+
+		p.read(stdout)
+
+		if err := cmd.Wait(); err != nil {
+			slog.Error("providers", "external", p.path, "exit", err)
+		}
+
+		p.mu.Lock()
+		p.running = false
+
+		for id, c := range p.pending {
+			close(c)
+			delete(p.pending, id)
+		}
+		p.mu.Unlock()
+	}()
+
+	return done, nil
+}
+
+// kill stops the process and waits until it exited.
+func (p *externalProvider) kill(done chan struct{}) {
+	p.mu.Lock()
+	cmd := p.cmd
+	p.mu.Unlock()
+
+	if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		slog.Error("providers", "external", p.path, "kill", err)
+	}
+
+	<-done
+}
+
+// stop ends the process for good, it isn't restarted afterwards. Providers with the shutdown capability are asked to exit first.
+func (p *externalProvider) stop() {
+	if p.stopped.Swap(true) {
+		return
+	}
+
+	if slices.Contains(p.getInfo().Capabilities, "shutdown") {
+		p.shutdown()
+	}
+
+	p.mu.Lock()
+	done := p.done
+	p.mu.Unlock()
+
+	p.kill(done)
+}
+
+func (p *externalProvider) getInfo() externalInfo {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.info
+}
+
+func (p *externalProvider) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), externalMaxMessage)
+
+	for scanner.Scan() {
+		var res rpcResponse
+
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			slog.Error("providers", "external", p.path, "decode", err)
+			continue
+		}
+
+		p.mu.Lock()
+		c, ok := p.pending[res.ID]
+		delete(p.pending, res.ID)
+		p.mu.Unlock()
+
+		if ok {
+			c <- res
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		slog.Error("providers", "external", p.path, "read", err)
+	}
+}
+
+// supervise restarts the process with an exponential backoff whenever it exits, unless it was stopped.
+func (p *externalProvider) supervise(done chan struct{}) {
+	backoff := time.Second
+
+	for {
+		started := time.Now()
+		<-done
+
+		if p.stopped.Load() {
+			return
+		}
+
+		if time.Since(started) > time.Minute {
+			backoff = time.Second
+		}
+
+		slog.Error("providers", "external", p.path, "restart", backoff)
+		time.Sleep(backoff)
+		backoff = min(backoff*2, externalMaxBackoff)
+
+		var err error
+
+		done, err = p.start()
+		if err != nil {
+			slog.Error("providers", "external", p.path, "start", err)
+
+			done = make(chan struct{})
+			close(done)
+
+			continue
+		}
+
+		if err := p.initialize(); err != nil {
+			slog.Error("providers", "external", p.path, "initialize", err)
+			p.kill(done)
+
+			continue
+		}
+
+		p.timeouts.Store(0)
+
+		if p.setup.Load() {
+			go p.runSetup()
+		}
+	}
+}
+
+func (p *externalProvider) call(method string, params, result any, timeout time.Duration) error {
+	id := p.id.Add(1)
+	c := make(chan rpcResponse, 1)
+
+	b, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return errExternalExited
+	}
+
+	p.pending[id] = c
+	stdin := p.stdin
+	p.mu.Unlock()
+
+	p.writeMu.Lock()
+	_, err = stdin.Write(append(b, '\n'))
+	p.writeMu.Unlock()
+
+	if err != nil {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+
+		return err
+	}
+
+	var timer <-chan time.Time
+
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+
+		timer = t.C
+	}
+
+	select {
+	case res, ok := <-c:
+		if !ok {
+			return errExternalExited
+		}
+
+		p.timeouts.Store(0)
+
+		if res.Error != nil {
+			return res.Error
+		}
+
+		if result == nil || len(res.Result) == 0 {
+			return nil
+		}
+
+		return json.Unmarshal(res.Result, result)
+	case <-timer:
+		p.mu.Lock()
+		delete(p.pending, id)
+		cmd := p.cmd
+		p.mu.Unlock()
+
+		// a hung process is restarted by the supervisor.
+		if p.timeouts.Add(1) >= externalMaxTimeouts && !p.stopped.Load() {
+			slog.Error("providers", "external", p.path, "restart", "calls keep timing out")
+			p.timeouts.Store(0)
+
+			if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				slog.Error("providers", "external", p.path, "kill", err)
+			}
+		}
+
+		return fmt.Errorf("%s timed out after %s", method, timeout)
+	}
+}
+
+func (p *externalProvider) initialize() error {
+	info := externalInfo{}
+
+	if err := p.call("initialize", nil, &info, externalCallTimeout); err != nil {
+		return err
+	}
+
+	if info.Name == "" {
+		return errors.New("provider did not report a name")
+	}
+
+	if p.name != "" && p.name != info.Name {
+		return fmt.Errorf("provider changed its name from %s to %s", p.name, info.Name)
+	}
+
+	if info.NamePretty == "" {
+		info.NamePretty = info.Name
+	}
+
+	p.mu.Lock()
+	p.info = info
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *externalProvider) runSetup() {
+	p.setup.Store(true)
+
+	if err := p.call("setup", nil, nil, externalLongCallTimeout); err != nil {
+		slog.Error(p.name, "setup", err)
+	}
+}
+
+// available reports the availability given by initialize. While the provider is unavailable, its health is checked
+// instead, as its dependencies might have appeared since. Providers without the health capability stay unavailable.
+func (p *externalProvider) available() bool {
+	info := p.getInfo()
+
+	if info.Available == nil || *info.Available {
+		return true
+	}
+
+	if !slices.Contains(info.Capabilities, "health") {
+		return false
+	}
+
+	if err := p.health(); err != nil {
+		slog.Debug(p.name, "available", err)
+		return false
+	}
+
+	p.mu.Lock()
+	available := true
+	p.info.Available = &available
+	p.mu.Unlock()
+
+	return true
+}
+
+func (p *externalProvider) printDoc() {
+	fmt.Println(p.getInfo().Doc)
+}
+
+func (p *externalProvider) hideFromProviderlist() bool {
+	return p.getInfo().HideFromProviderlist
+}
+
+func (p *externalProvider) icon() string {
+	return p.getInfo().Icon
+}
+
+func (p *externalProvider) query(conn net.Conn, query string, single bool, matcher common.Matcher, format uint8) []*pb.QueryResponse_Item {
+	entries := []*pb.QueryResponse_Item{}
+
+	err := p.call("query", externalQuery{
+		Query:   query,
+		Single:  single,
+		Matcher: matcher,
+	}, &entries, externalCallTimeout)
+	if err != nil {
+		slog.Error(p.name, "query", err)
+		return []*pb.QueryResponse_Item{}
+	}
+
+	for _, v := range entries {
+		if v.Provider == "" {
+			v.Provider = p.name
+		}
+	}
+
+	return entries
+}
+
+func (p *externalProvider) activate(single bool, identifier, action, query, args string, format uint8, conn net.Conn) {
+	err := p.call("activate", externalActivate{
+		Single:     single,
+		Identifier: identifier,
+		Action:     action,
+		Query:      query,
+		Args:       args,
+	}, nil, externalLongCallTimeout)
+	if err != nil {
+		slog.Error(p.name, "activate", err)
+	}
+}
+
+func (p *externalProvider) state(provider string) *pb.ProviderStateResponse {
+	res := &pb.ProviderStateResponse{}
+
+	if err := p.call("state", externalState{Provider: provider}, res, externalCallTimeout); err != nil {
+		slog.Error(p.name, "state", err)
+		return &pb.ProviderStateResponse{}
+	}
+
+	return res
+}
+
+// notify returns a hook calling a method without params or result.
+func (p *externalProvider) notify(method string) func() {
+	return func() {
+		if err := p.call(method, nil, nil, externalLongCallTimeout); err != nil {
+			slog.Error(p.name, method, err)
+		}
+	}
+}
+
+func (p *externalProvider) shutdown() {
+	if err := p.call("shutdown", nil, nil, externalCallTimeout); err != nil && !errors.Is(err, errExternalExited) {
+		slog.Error(p.name, "shutdown", err)
+	}
+}
+
+func (p *externalProvider) health() error {
+	return p.call("health", nil, nil, externalCallTimeout)
+}
+
+func (p *externalProvider) preview(identifier string) (string, string) {
+	res := externalPreview{}
+
+	if err := p.call("preview", externalIdentifier{Identifier: identifier}, &res, externalCallTimeout); err != nil {
+		slog.Error(p.name, "preview", err)
+		return "", ""
+	}
+
+	return res.Preview, res.PreviewType
+}
+
+func (p *externalProvider) complete(query string) []string {
+	res := []string{}
+
+	if err := p.call("complete", externalQuery{Query: query}, &res, externalCallTimeout); err != nil {
+		slog.Error(p.name, "complete", err)
+		return []string{}
+	}
+
+	return res
+}
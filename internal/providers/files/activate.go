@@ -39,7 +39,7 @@ func Activate(single bool, identifier, action string, query string, args string,
 			path = filepath.Dir(path)
 		}
 
-		run := strings.TrimSpace(fmt.Sprintf("%s xdg-open '%s'", common.LaunchPrefix(config.LaunchPrefix), path))
+		run := strings.TrimSpace(fmt.Sprintf("%s xdg-open '%s'", common.LaunchPrefix(getConfig().LaunchPrefix), path))
 
 		if common.ForceTerminalForFile(path) {
 			run = common.WrapWithTerminal(run)
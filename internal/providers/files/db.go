@@ -104,7 +104,7 @@ func getFile(identifier string) *File {
 	return &f
 }
 
-func getFilesByQuery(query string, _ bool) []File {
+func getFilesByQuery(query string, _ common.Matcher) []File {
 	var result []File
 
 	path := common.CacheFile("files.db")
@@ -154,3 +154,10 @@ func deleteFileByPath(path string) {
 		slog.Error(Name, "delete", err)
 	}
 }
+
+func deleteAllFiles() {
+	_, err := db.Exec("DELETE FROM files")
+	if err != nil {
+		slog.Error(Name, "delete", err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log/slog"
+	"math"
 	"net"
 	"strings"
 	"time"
@@ -11,13 +12,31 @@ import (
 	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
 )
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	config := getConfig()
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
 	actions := []string{ActionOpen, ActionOpenDir, ActionCopyFile, ActionCopyPath}
 
-	results := getFilesByQuery(query, exact)
+	results := getFilesByQuery(query, matcher)
+
+	// the candidates change with every query, so the corpus is local and only used to score them in parallel.
+	scores := make(map[int]common.BatchResult)
+
+	if query != "" {
+		corpus := common.NewCorpus(len(results))
+
+		for _, v := range results {
+			corpus.Add(v.Path)
+		}
+
+		for _, v := range corpus.Score(query, matcher, math.MinInt32, 0) {
+			scores[v.Index] = v
+		}
+	}
 
 	for k, v := range results {
 		p := v.Path
@@ -44,12 +63,16 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 		}
 
 		if query != "" {
-			score, pos, start := common.FuzzyScore(query, v.Path, exact)
-			entry.Score = score
+			res, ok := scores[k]
+			if !ok {
+				res.Start = -1
+			}
+
+			entry.Score = res.Score
 			entry.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
-				Start:     start,
+				Start:     res.Start,
 				Field:     "text",
-				Positions: pos,
+				Positions: res.Positions,
 			}
 		}
 
@@ -13,6 +13,8 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/v2/internal/util"
@@ -26,11 +28,12 @@ import (
 var readme string
 
 var (
-	Name         = "files"
-	NamePretty   = "Files"
-	config       *Config
-	watcher      *fsnotify.Watcher
-	ignoreRegexp []*regexp.Regexp
+	Name       = "files"
+	NamePretty = "Files"
+	ABIVersion = common.ProviderABIVersion
+	current    atomic.Pointer[Config]
+	watcher    *fsnotify.Watcher
+	scanMu     sync.Mutex
 )
 
 type IgnoredPreview struct {
@@ -46,31 +49,69 @@ type Config struct {
 	IgnoreWatching []string         `koanf:"ignore_watching" desc:"paths will not be watched" default:""`
 	SearchDirs     []string         `koanf:"search_dirs" desc:"directories to search for files" default:"$HOME"`
 	FdFlags        string           `koanf:"fd_flags" desc:"flags for fd" default:"--ignore-vcs --type file --type directory"`
+
+	ignore []*regexp.Regexp
 }
 
 func Setup() {
-	start := time.Now()
-
 	err := openDB()
 	if err != nil {
 		slog.Error(Name, "setup", err)
 		return
 	}
 
-	config = &Config{
-		Config: common.Config{
-			Icon:     "folder",
-			MinScore: 20,
-		},
-		LaunchPrefix: "",
-		SearchDirs:   []string{},
-		FdFlags:      "--ignore-vcs --type file --type directory",
+	loadConfig()
+
+	watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	common.LoadConfig(Name, config)
+	deleteChan := make(chan string)
+	regularChan := make(chan string)
 
-	if config.NamePretty != "" {
-		NamePretty = config.NamePretty
+	go handleDelete(deleteChan)
+	go handleRegular(regularChan)
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					continue
+				}
+
+				if event.Op == fsnotify.Remove || event.Op == fsnotify.Rename {
+					deleteChan <- event.Name
+					continue
+				}
+
+				regularChan <- event.Name
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	scan(getConfig(), false)
+}
+
+// scan indexes and watches the search dirs with fd. With reset, files and
+// watches of a previous scan are dropped first. Scans don't overlap.
+func scan(config *Config, reset bool) {
+	scanMu.Lock()
+	defer scanMu.Unlock()
+
+	start := time.Now()
+
+	if reset {
+		for _, path := range watcher.WatchList() {
+			watcher.Remove(path)
+		}
+
+		deleteAllFiles()
 	}
 
 	searchDirs := config.SearchDirs
@@ -79,16 +120,6 @@ func Setup() {
 		searchDirs = []string{home}
 	}
 
-	for _, v := range config.IgnoredDirs {
-		r, err := regexp.Compile(v)
-		if err != nil {
-			slog.Error(Name, "ignoredirs regexp", err)
-			continue
-		}
-
-		ignoreRegexp = append(ignoreRegexp, r)
-	}
-
 	cmd := exec.Command("fd", ".")
 	cmd.Args = append(cmd.Args, searchDirs...)
 	cmd.Args = append(cmd.Args, strings.Fields(config.FdFlags)...)
@@ -104,11 +135,6 @@ func Setup() {
 		os.Exit(1)
 	}
 
-	watcher, err = fsnotify.NewWatcher()
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	for _, path := range config.SearchDirs {
 
 		if !slices.Contains(config.IgnoreWatching, path) {
@@ -136,35 +162,11 @@ func Setup() {
 		}
 	}
 
-	deleteChan := make(chan string)
-	regularChan := make(chan string)
-
-	go handleDelete(deleteChan)
-	go handleRegular(regularChan)
+	done := make(chan struct{})
 
 	go func() {
-		for {
-			select {
-			case event, ok := <-watcher.Events:
-				if !ok {
-					continue
-				}
-
-				if event.Op == fsnotify.Remove || event.Op == fsnotify.Rename {
-					deleteChan <- event.Name
-					continue
-				}
-
-				regularChan <- event.Name
-			case _, ok := <-watcher.Errors:
-				if !ok {
-					return
-				}
-			}
-		}
-	}()
+		defer close(done)
 
-	go func() {
 		scanner := bufio.NewScanner(stdout)
 
 		batch := make([]File, 0, 5000)
@@ -174,7 +176,7 @@ func Setup() {
 			path := strings.TrimSpace(scanner.Text())
 
 			if len(path) > 0 {
-				for _, v := range ignoreRegexp {
+				for _, v := range config.ignore {
 					if v.Match([]byte(path)) {
 						continue outer
 					}
@@ -222,6 +224,8 @@ func Setup() {
 		}
 	}()
 
+	<-done
+
 	if err := cmd.Wait(); err != nil {
 		slog.Error(Name, "cmd wait", err)
 	}
@@ -229,6 +233,56 @@ func Setup() {
 	slog.Info(Name, "time", time.Since(start))
 }
 
+func getConfig() *Config {
+	return current.Load()
+}
+
+func loadConfig() {
+	config := &Config{
+		Config: common.Config{
+			Icon:     "folder",
+			MinScore: 20,
+		},
+		LaunchPrefix: "",
+		SearchDirs:   []string{},
+		FdFlags:      "--ignore-vcs --type file --type directory",
+	}
+
+	common.LoadConfig(Name, config)
+
+	if config.NamePretty != "" {
+		NamePretty = config.NamePretty
+	}
+
+	for _, v := range config.IgnoredDirs {
+		r, err := regexp.Compile(v)
+		if err != nil {
+			slog.Error(Name, "ignoredirs regexp", err)
+			continue
+		}
+
+		config.ignore = append(config.ignore, r)
+	}
+
+	current.Store(config)
+}
+
+// Reload swaps in the new config. If search_dirs, ignored_dirs or fd_flags
+// changed, the index is dropped and the search dirs are scanned again.
+func Reload() {
+	old := getConfig()
+
+	loadConfig()
+
+	config := getConfig()
+
+	if old == nil || (slices.Equal(old.SearchDirs, config.SearchDirs) && slices.Equal(old.IgnoredDirs, config.IgnoredDirs) && old.FdFlags == config.FdFlags) {
+		return
+	}
+
+	go scan(config, true)
+}
+
 func Available() bool {
 	p, err := exec.LookPath("fd")
 
@@ -323,11 +377,11 @@ func PrintDoc() {
 }
 
 func Icon() string {
-	return config.Icon
+	return getConfig().Icon
 }
 
 func HideFromProviderlist() bool {
-	return config.HideFromProviderlist
+	return getConfig().HideFromProviderlist
 }
 
 func State(provider string) *pb.ProviderStateResponse {
@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// start runs the provider's setup, or defers it until first use if the provider is configured to be lazy.
+// The returned provider has to be used instead of the given one.
+func start(p Provider) Provider {
+	if !common.IsLazy(*p.Name) {
+		go p.Setup()
+		return p
+	}
+
+	return lazy(p)
+}
+
+// lazy wraps the provider so Setup runs on the first request. Concurrent requests wait until it finished.
+func lazy(p Provider) Provider {
+	var once sync.Once
+	var ready atomic.Bool
+
+	original := p.Setup
+	setup := func() {
+		original()
+		ready.Store(true)
+	}
+
+	wait := func() { once.Do(setup) }
+
+	// an explicit Setup always runs, but counts as the initial one if nothing triggered it yet.
+	p.Setup = func() {
+		ran := false
+
+		once.Do(func() {
+			ran = true
+			setup()
+		})
+
+		if !ran {
+			setup()
+		}
+	}
+
+	// the providerlist asks every provider for these, so they use the user's config until the provider is set up.
+	icon := p.Icon
+	p.Icon = func() string {
+		if !ready.Load() {
+			return common.ProviderSettings(*p.Name).Icon
+		}
+
+		return icon()
+	}
+
+	hide := p.HideFromProviderlist
+	p.HideFromProviderlist = func() bool {
+		if !ready.Load() {
+			return common.ProviderSettings(*p.Name).HideFromProviderlist
+		}
+
+		return hide()
+	}
+
+	query := p.Query
+	p.Query = func(conn net.Conn, text string, single bool, matcher common.Matcher, format uint8) []*pb.QueryResponse_Item {
+		wait()
+		return query(conn, text, single, matcher, format)
+	}
+
+	state := p.State
+	p.State = func(provider string) *pb.ProviderStateResponse {
+		wait()
+		return state(provider)
+	}
+
+	activate := p.Activate
+	p.Activate = func(single bool, identifier, action, query, args string, format uint8, conn net.Conn) {
+		wait()
+		activate(single, identifier, action, query, args, format, conn)
+	}
+
+	// the config is read by the setup anyway, so reloading only matters once it ran.
+	if reload := p.Reload; reload != nil {
+		p.Reload = func() {
+			if ready.Load() {
+				reload()
+			}
+		}
+	}
+
+	if preview := p.Preview; preview != nil {
+		p.Preview = func(identifier string) (string, string) {
+			wait()
+			return preview(identifier)
+		}
+	}
+
+	if complete := p.Complete; complete != nil {
+		p.Complete = func(query string) []string {
+			wait()
+			return complete(query)
+		}
+	}
+
+	return p
+}
@@ -2,6 +2,8 @@
 package providers
 
 import (
+	"errors"
+	"fmt"
 	"io/fs"
 	"log/slog"
 	"net"
@@ -11,6 +13,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/abenz1267/elephant/v2/pkg/common"
 	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
@@ -32,12 +35,39 @@ type Provider struct {
 	HideFromProviderlist func() bool
 	Icon                 func() string
 	Activate             func(single bool, identifier, action, query, args string, format uint8, conn net.Conn)
-	Query                func(conn net.Conn, query string, single bool, exact bool, format uint8) []*pb.QueryResponse_Item
+	Query                func(conn net.Conn, query string, single bool, matcher common.Matcher, format uint8) []*pb.QueryResponse_Item
+
+	// optional, nil if the provider doesn't export them
+	Shutdown func()
+	Reload   func()
+	Refresh  func()
+	Health   func() error
+	Preview  func(identifier string) (string, string)
+	Complete func(query string) []string
+}
+
+// BrokenProvider is a provider that couldn't be loaded.
+type BrokenProvider struct {
+	Path  string
+	Error string
 }
 
 var (
 	Providers      map[string]Provider
 	QueryProviders map[uint32][]string
+	Broken         []BrokenProvider
+
+	// mu guards Providers, the disabled, unavailable and ignored ones, as they can be changed at runtime.
+	mu sync.RWMutex
+
+	// paths of providers skipped via IgnoredProviders, so they can be enabled later on.
+	ignoredPaths map[string]string
+
+	// paths of loaded providers that aren't built in, so they can be loaded again after being shut down.
+	paths map[string]string
+
+	// providers that were not available when loading, they are checked again periodically.
+	unavailable map[string]Provider
 )
 
 func Load(setup bool) {
@@ -48,13 +78,65 @@ func Load(setup bool) {
 	have := []string{}
 	dirs := append(common.ConfigDirs(), os.Getenv("ELEPHANT_PROVIDER_DIR"))
 
+	mu.Lock()
 	Providers = make(map[string]Provider)
 	QueryProviders = make(map[uint32][]string)
+	Broken = []BrokenProvider{}
+	ignoredPaths = make(map[string]string)
+	paths = make(map[string]string)
+	disabled = make(map[string]Provider)
+	unavailable = make(map[string]Provider)
+	mu.Unlock()
 
 	if os.Getenv("ELEPHANT_DEV") == "true" {
 		dirs = []string{"/tmp/elephant/providers"}
 	}
 
+	register := func(path string, provider Provider) {
+		available := provider.Available()
+
+		if setup && available {
+			provider = start(provider)
+		}
+
+		mu.Lock()
+		if _, ok := builtin[*provider.Name]; !ok {
+			paths[*provider.Name] = path
+		}
+
+		if available {
+			Providers[*provider.Name] = provider
+		} else {
+			unavailable[*provider.Name] = provider
+		}
+		mu.Unlock()
+
+		if !available {
+			slog.Info("providers", "unavailable", *provider.Name)
+			return
+		}
+
+		mut.Lock()
+		have = append(have, filepath.Base(path))
+		mut.Unlock()
+
+		slog.Info("providers", "loaded", *provider.Name)
+	}
+
+	skip := func(path string, err error) {
+		mut.Lock()
+		Broken = append(Broken, BrokenProvider{Path: path, Error: err.Error()})
+		mut.Unlock()
+	}
+
+	for name, provider := range builtin {
+		if slices.Contains(ignored, name) {
+			continue
+		}
+
+		register(name+".so", provider)
+	}
+
 	for _, v := range dirs {
 		if !common.FileExists(v) {
 			continue
@@ -74,113 +156,174 @@ func Load(setup bool) {
 			done := slices.Contains(have, filepath.Base(path))
 			mut.Unlock()
 
-			fn := strings.TrimSuffix(filepath.Base(path), ".so")
+			fn := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			isProvider := isProviderFile(path, d)
 
 			if slices.Contains(ignored, fn) {
 				mut.Lock()
 				have = append(have, filepath.Base(path))
 				mut.Unlock()
 
+				mu.Lock()
+				if _, ok := ignoredPaths[fn]; !ok && isProvider {
+					ignoredPaths[fn] = path
+				}
+				mu.Unlock()
+
+				return nil
+			}
+
+			// plugins, external and Lua providers with the name of a built-in one are shadowed by it.
+			if _, ok := builtin[fn]; ok {
 				return nil
 			}
 
-			if !done && filepath.Ext(path) == ".so" {
-				p, err := plugin.Open(path)
+			if !done && isProvider {
+				provider, err := loadPath(path)
 				if err != nil {
 					slog.Error("providers", "load", path, "err", err)
+					skip(path, err)
 					return nil
 				}
 
-				name, err := p.Lookup("Name")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+				register(path, provider)
+			}
 
-				namePretty, err := p.Lookup("NamePretty")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+			return err
+		}
 
-				activateFunc, err := p.Lookup("Activate")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+		if err := fastwalk.Walk(&conf, v, walkFn); err != nil {
+			slog.Error("providers", "load", err)
+			os.Exit(1)
+		}
+	}
 
-				hideFromProviderlistFunc, err := p.Lookup("HideFromProviderlist")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+	// a broken copy doesn't matter if the same provider was loaded from another directory.
+	Broken = slices.DeleteFunc(Broken, func(b BrokenProvider) bool {
+		return slices.Contains(have, filepath.Base(b.Path))
+	})
 
-				availableFunc, err := p.Lookup("Available")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+	mu.Lock()
+	for k := range Providers {
+		delete(unavailable, k)
+	}
+	mu.Unlock()
 
-				queryFunc, err := p.Lookup("Query")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+	if interval := common.GetElephantConfig().AvailabilityInterval; setup && interval > 0 && len(unavailable) > 0 {
+		go recheck(time.Duration(interval) * time.Second)
+	}
+}
 
-				iconFunc, err := p.Lookup("Icon")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+// isProviderFile reports whether the path is a Go plugin, an external provider or a Lua provider.
+func isProviderFile(path string, d fs.DirEntry) bool {
+	switch filepath.Ext(path) {
+	case ".so":
+		return true
+	case ExternalExt:
+		return !d.IsDir()
+	case LuaExt:
+		return !d.IsDir() && isLuaProvider(path)
+	}
 
-				printDocFunc, err := p.Lookup("PrintDoc")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+	return false
+}
 
-				setupFunc, err := p.Lookup("Setup")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+func loadPath(path string) (Provider, error) {
+	switch filepath.Ext(path) {
+	case ExternalExt:
+		return loadExternal(path)
+	case LuaExt:
+		return loadLua(path)
+	}
 
-				stateFunc, err := p.Lookup("State")
-				if err != nil {
-					slog.Error("providers", "load", err, "provider", path)
-				}
+	return loadPlugin(path)
+}
 
-				provider := Provider{
-					Icon:                 iconFunc.(func() string),
-					Setup:                setupFunc.(func()),
-					Name:                 name.(*string),
-					Activate:             activateFunc.(func(bool, string, string, string, string, uint8, net.Conn)),
-					Query:                queryFunc.(func(net.Conn, string, bool, bool, uint8) []*pb.QueryResponse_Item),
-					NamePretty:           namePretty.(*string),
-					HideFromProviderlist: hideFromProviderlistFunc.(func() bool),
-					PrintDoc:             printDocFunc.(func()),
-					Available:            availableFunc.(func() bool),
-					State:                stateFunc.(func(string) *pb.ProviderStateResponse),
-				}
+// loadPlugin opens a Go plugin and validates its ABI version and all symbols before using any of them.
+func loadPlugin(path string) (Provider, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return Provider{}, err
+	}
 
-				available := provider.Available()
+	errs := []string{}
 
-				if setup && available {
-					go provider.Setup()
-				}
+	abi := lookup[*int](p, "ABIVersion", &errs)
+	if abi == nil {
+		return Provider{}, fmt.Errorf("not a provider or built for an older elephant: %s", strings.Join(errs, ", "))
+	}
 
-				if available {
-					mut.Lock()
-					Providers[*provider.Name] = provider
-					mut.Unlock()
-				}
+	if *abi != common.ProviderABIVersion {
+		return Provider{}, fmt.Errorf("provider ABI version %d, elephant requires %d", *abi, common.ProviderABIVersion)
+	}
 
-				slog.Info("providers", "loaded", *provider.Name)
+	provider := Provider{
+		Icon:                 lookup[func() string](p, "Icon", &errs),
+		Setup:                lookup[func()](p, "Setup", &errs),
+		Name:                 lookup[*string](p, "Name", &errs),
+		Activate:             lookup[func(bool, string, string, string, string, uint8, net.Conn)](p, "Activate", &errs),
+		Query:                lookup[func(net.Conn, string, bool, common.Matcher, uint8) []*pb.QueryResponse_Item](p, "Query", &errs),
+		NamePretty:           lookup[*string](p, "NamePretty", &errs),
+		HideFromProviderlist: lookup[func() bool](p, "HideFromProviderlist", &errs),
+		PrintDoc:             lookup[func()](p, "PrintDoc", &errs),
+		Available:            lookup[func() bool](p, "Available", &errs),
+		State:                lookup[func(string) *pb.ProviderStateResponse](p, "State", &errs),
+	}
 
-				if available {
-					mut.Lock()
-					have = append(have, filepath.Base(path))
-					mut.Unlock()
-				}
-			}
+	provider.Shutdown = lookupOptional[func()](p, "Shutdown", &errs)
+	provider.Reload = lookupOptional[func()](p, "Reload", &errs)
+	provider.Refresh = lookupOptional[func()](p, "Refresh", &errs)
+	provider.Health = lookupOptional[func() error](p, "Health", &errs)
+	provider.Preview = lookupOptional[func(string) (string, string)](p, "Preview", &errs)
+	provider.Complete = lookupOptional[func(string) []string](p, "Complete", &errs)
 
-			return err
-		}
+	if len(errs) != 0 {
+		return Provider{}, errors.New(strings.Join(errs, ", "))
+	}
 
-		if err := fastwalk.Walk(&conf, v, walkFn); err != nil {
-			slog.Error("providers", "load", err)
-			os.Exit(1)
+	return provider, nil
+}
+
+// lookup finds a symbol and checks its type, problems are appended to errs.
+func lookup[T any](p *plugin.Plugin, name string, errs *[]string) T {
+	var zero T
+
+	sym, err := p.Lookup(name)
+	if err != nil {
+		*errs = append(*errs, fmt.Sprintf("missing symbol %s", name))
+		return zero
+	}
+
+	val, ok := sym.(T)
+	if !ok {
+		*errs = append(*errs, fmt.Sprintf("symbol %s is %T, expected %T", name, sym, zero))
+		return zero
+	}
+
+	return val
+}
+
+// lookupOptional is like lookup, but a missing symbol is not an error.
+func lookupOptional[T any](p *plugin.Plugin, name string, errs *[]string) T {
+	if _, err := p.Lookup(name); err != nil {
+		var zero T
+		return zero
+	}
+
+	return lookup[T](p, name, errs)
+}
+
+// Shutdown calls the shutdown hook of every provider exporting one.
+func Shutdown() {
+	var wg sync.WaitGroup
+
+	for _, v := range All() {
+		if v.Shutdown == nil {
+			continue
 		}
+
+		wg.Go(v.Shutdown)
 	}
+
+	wg.Wait()
 }
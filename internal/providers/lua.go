@@ -0,0 +1,400 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaExt is the file extension of providers written in Lua.
+const LuaExt = ".lua"
+
+const (
+	// luaCallTimeout limits queries and other calls made while the user waits.
+	luaCallTimeout = 10 * time.Second
+	// luaLongCallTimeout limits Setup and Activate, which may run longer tasks.
+	luaLongCallTimeout = 2 * time.Minute
+)
+
+// luaProvider is a provider implemented by a Lua script. A Lua state isn't safe for concurrent use, so every call holds mu.
+// Calls are aborted after a timeout, the state is dropped then and the script is loaded again on the next call.
+type luaProvider struct {
+	path   string
+	source string
+	name   string
+
+	namePretty string
+	icon       string
+	doc        string
+	hide       bool
+
+	mu    sync.Mutex
+	l     *lua.LState
+	setup bool
+}
+
+// isLuaProvider reports whether a .lua file is a provider. Lua files elsewhere in the config dir, f.e. menus, are ignored.
+func isLuaProvider(path string) bool {
+	dir := filepath.Dir(path)
+
+	return filepath.Base(dir) == "providers" || dir == filepath.Clean(os.Getenv("ELEPHANT_PROVIDER_DIR"))
+}
+
+func loadLua(path string) (Provider, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Provider{}, err
+	}
+
+	p := &luaProvider{
+		path:   path,
+		source: string(b),
+	}
+
+	if err := p.load(); err != nil {
+		return Provider{}, err
+	}
+
+	p.name = p.globalString("Name")
+	p.namePretty = p.globalString("NamePretty")
+	p.icon = p.globalString("Icon")
+	p.doc = p.globalString("Doc")
+	p.hide = lua.LVAsBool(p.l.GetGlobal("HideFromProviderlist"))
+
+	if p.name == "" {
+		p.l.Close()
+		return Provider{}, errors.New("missing Name")
+	}
+
+	if p.namePretty == "" {
+		p.namePretty = p.name
+	}
+
+	for _, v := range []string{"Query", "Activate"} {
+		if p.l.GetGlobal(v).Type() != lua.LTFunction {
+			p.l.Close()
+			return Provider{}, fmt.Errorf("missing function %s", v)
+		}
+	}
+
+	return Provider{
+		Name:                 &p.name,
+		NamePretty:           &p.namePretty,
+		Available:            p.available,
+		PrintDoc:             p.printDoc,
+		State:                p.state,
+		Setup:                p.runSetup,
+		HideFromProviderlist: func() bool { return p.hide },
+		Icon:                 func() string { return p.icon },
+		Activate:             p.activate,
+		Query:                p.query,
+	}, nil
+}
+
+// load runs the script with a new state.
+func (p *luaProvider) load() error {
+	l := lua.NewState()
+
+	l.SetGlobal("jsonEncode", l.NewFunction(common.JSONEncode))
+	l.SetGlobal("jsonDecode", l.NewFunction(common.JSONDecode))
+	l.SetGlobal("matchScore", l.NewFunction(luaMatchScore))
+
+	if err := p.run(l, luaCallTimeout, func() error {
+		return l.DoString(p.source)
+	}); err != nil {
+		if !errors.Is(err, common.ErrLuaTimeout) {
+			l.Close()
+		}
+
+		return err
+	}
+
+	p.l = l
+
+	return nil
+}
+
+// ensure loads the script again if the last state was dropped. Setup runs again if it ran before. Has to be called holding mu.
+func (p *luaProvider) ensure() bool {
+	if p.l != nil {
+		return true
+	}
+
+	if err := p.load(); err != nil {
+		slog.Error(p.name, "load", err)
+		return false
+	}
+
+	if p.setup {
+		p.call("Setup", luaLongCallTimeout, 0)
+	}
+
+	return p.l != nil
+}
+
+// run calls fn with a deadline. Lua code is interrupted once it's exceeded, while calls blocking in Go, f.e. os.execute,
+// are left running. In both cases the state can't be used anymore and is closed as soon as fn returns.
+func (p *luaProvider) run(l *lua.LState, timeout time.Duration, fn func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	l.SetContext(ctx)
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("%v", r)
+			}
+		}()
+
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		if ctx.Err() == nil {
+			l.RemoveContext()
+			return err
+		}
+
+		l.Close()
+	case <-ctx.Done():
+		go func() {
+			<-done
+			l.Close()
+		}()
+	}
+
+	return fmt.Errorf("%w after %s", common.ErrLuaTimeout, timeout)
+}
+
+func (p *luaProvider) globalString(name string) string {
+	if val, ok := p.l.GetGlobal(name).(lua.LString); ok {
+		return string(val)
+	}
+
+	return ""
+}
+
+// call calls a global function if it exists. The returned values have to be used while holding mu.
+// If the call times out, the state is dropped.
+func (p *luaProvider) call(name string, timeout time.Duration, nret int, args ...lua.LValue) ([]lua.LValue, bool) {
+	if !p.ensure() {
+		return nil, false
+	}
+
+	l := p.l
+
+	fn := l.GetGlobal(name)
+	if fn.Type() != lua.LTFunction {
+		return nil, false
+	}
+
+	res := make([]lua.LValue, nret)
+
+	err := p.run(l, timeout, func() error {
+		if err := l.CallByParam(lua.P{
+			Fn:      fn,
+			NRet:    nret,
+			Protect: true,
+		}, args...); err != nil {
+			return err
+		}
+
+		for i := range nret {
+			res[i] = l.Get(i - nret)
+		}
+
+		l.Pop(nret)
+
+		return nil
+	})
+	if err != nil {
+		slog.Error(p.name, name, err)
+
+		if errors.Is(err, common.ErrLuaTimeout) {
+			p.l = nil
+		}
+
+		return nil, false
+	}
+
+	return res, true
+}
+
+func (p *luaProvider) runSetup() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.setup = true
+
+	p.call("Setup", luaLongCallTimeout, 0)
+}
+
+func (p *luaProvider) available() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.ensure() {
+		return false
+	}
+
+	if p.l.GetGlobal("Available").Type() != lua.LTFunction {
+		return true
+	}
+
+	res, ok := p.call("Available", luaCallTimeout, 1)
+
+	return ok && lua.LVAsBool(res[0])
+}
+
+func (p *luaProvider) printDoc() {
+	fmt.Println(p.doc)
+}
+
+func (p *luaProvider) query(conn net.Conn, query string, single bool, matcher common.Matcher, format uint8) []*pb.QueryResponse_Item {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries := []*pb.QueryResponse_Item{}
+
+	res, ok := p.call("Query", luaCallTimeout, 1, lua.LString(query), lua.LBool(single), lua.LBool(matcher == common.MatcherExact), lua.LString(matcher))
+	if !ok {
+		return entries
+	}
+
+	table, ok := res[0].(*lua.LTable)
+	if !ok {
+		return entries
+	}
+
+	table.ForEach(func(_, value lua.LValue) {
+		item, ok := value.(*lua.LTable)
+		if !ok {
+			return
+		}
+
+		e := &pb.QueryResponse_Item{
+			Identifier:  lua.LVAsString(item.RawGetString("Identifier")),
+			Text:        lua.LVAsString(item.RawGetString("Text")),
+			Subtext:     lua.LVAsString(item.RawGetString("Subtext")),
+			Icon:        lua.LVAsString(item.RawGetString("Icon")),
+			Preview:     lua.LVAsString(item.RawGetString("Preview")),
+			PreviewType: lua.LVAsString(item.RawGetString("PreviewType")),
+			Score:       int32(lua.LVAsNumber(item.RawGetString("Score"))),
+			Actions:     luaStrings(item.RawGetString("Actions")),
+			State:       luaStrings(item.RawGetString("State")),
+			Type:        pb.QueryResponse_REGULAR,
+			Provider:    p.name,
+		}
+
+		if e.Icon == "" {
+			e.Icon = p.icon
+		}
+
+		if fuzzy, ok := item.RawGetString("Fuzzy").(*lua.LTable); ok {
+			e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
+				Start:     int32(lua.LVAsNumber(fuzzy.RawGetString("Start"))),
+				Field:     lua.LVAsString(fuzzy.RawGetString("Field")),
+				Positions: luaInts(fuzzy.RawGetString("Positions")),
+			}
+		}
+
+		entries = append(entries, e)
+	})
+
+	return entries
+}
+
+func (p *luaProvider) activate(single bool, identifier, action, query, args string, format uint8, conn net.Conn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.call("Activate", luaLongCallTimeout, 0, lua.LString(identifier), lua.LString(action), lua.LString(query), lua.LString(args))
+}
+
+func (p *luaProvider) state(provider string) *pb.ProviderStateResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	res, ok := p.call("State", luaCallTimeout, 1, lua.LString(provider))
+	if !ok {
+		return &pb.ProviderStateResponse{}
+	}
+
+	table, ok := res[0].(*lua.LTable)
+	if !ok {
+		return &pb.ProviderStateResponse{}
+	}
+
+	return &pb.ProviderStateResponse{
+		States:  luaStrings(table.RawGetString("States")),
+		Actions: luaStrings(table.RawGetString("Actions")),
+	}
+}
+
+// luaMatchScore exposes common.MatchScore as matchScore(query, text, matcher) => score, positions, start. The score is 0 if the text doesn't match.
+func luaMatchScore(L *lua.LState) int {
+	matcher := common.Matcher(L.OptString(3, ""))
+	if !matcher.Valid() {
+		L.ArgError(3, "unknown matcher")
+	}
+
+	score, positions, start := common.MatchScore(L.CheckString(1), L.CheckString(2), matcher)
+
+	if start < 0 {
+		score = 0
+	}
+
+	pos := L.NewTable()
+
+	for _, v := range positions {
+		pos.Append(lua.LNumber(v))
+	}
+
+	L.Push(lua.LNumber(score))
+	L.Push(pos)
+	L.Push(lua.LNumber(start))
+
+	return 3
+}
+
+func luaStrings(val lua.LValue) []string {
+	res := []string{}
+
+	if table, ok := val.(*lua.LTable); ok {
+		table.ForEach(func(_, v lua.LValue) {
+			if str, ok := v.(lua.LString); ok {
+				res = append(res, string(str))
+			}
+		})
+	}
+
+	return res
+}
+
+func luaInts(val lua.LValue) []int32 {
+	res := []int32{}
+
+	if table, ok := val.(*lua.LTable); ok {
+		table.ForEach(func(_, v lua.LValue) {
+			if num, ok := v.(lua.LNumber); ok {
+				res = append(res, int32(num))
+			}
+		})
+	}
+
+	return res
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	_ "embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"os"
 	"os/exec"
@@ -23,6 +25,7 @@ import (
 var (
 	Name       = "menus"
 	NamePretty = "Menus"
+	ABIVersion = common.ProviderABIVersion
 	h          = history.Load(Name)
 )
 
@@ -36,7 +39,10 @@ func PrintDoc() {
 	util.PrintConfig(common.Menu{}, Name)
 }
 
-func Setup() {}
+func Setup() {
+	common.WatchLuaMenus()
+	common.RefreshMenus()
+}
 
 func Available() bool {
 	return true
@@ -53,7 +59,7 @@ func Activate(single bool, identifier, action string, query string, args string,
 	case ActionGoParent:
 		identifier = strings.TrimPrefix(identifier, "menus:")
 
-		for _, v := range common.Menus {
+		for _, v := range common.AllMenus() {
 			if identifier == v.Name {
 				handlers.ProviderUpdated <- fmt.Sprintf("%s:%s", Name, v.Parent)
 				break
@@ -87,8 +93,19 @@ func Activate(single bool, identifier, action string, query string, args string,
 
 		terminal := false
 
-		if v, ok := common.Menus[m]; ok {
-			for _, entry := range v.Entries {
+		if d := common.GetDmenu(m); d != nil {
+			for _, entry := range d.Entries {
+				if identifier == entry.Identifier {
+					common.SelectDmenu(m, entry)
+					break
+				}
+			}
+
+			return
+		}
+
+		if v, ok := common.GetMenu(m); ok {
+			for _, entry := range v.CurrentEntries() {
 				if identifier == entry.Identifier {
 					menu = v
 					e = entry
@@ -122,6 +139,10 @@ func Activate(single bool, identifier, action string, query string, args string,
 			}
 		}
 
+		if run == "" && menu == nil {
+			return
+		}
+
 		if run == "" {
 			if len(menu.Actions) != 0 {
 				if val, ok := menu.Actions[action]; ok {
@@ -138,36 +159,69 @@ func Activate(single bool, identifier, action string, query string, args string,
 			return
 		}
 
+		fields := []common.Input{}
+		inputs := map[string]string{}
+
+		if menu != nil {
+			var err error
+
+			fields = menu.ActionInputs(&e)[inputAction(action)]
+
+			inputs, err = common.InputValues(fields, args)
+			if err != nil {
+				slog.Error(Name, "inputs", err, "menu", menu.Name, "action", action)
+				return
+			}
+		}
+
 		if after, ok := strings.CutPrefix(run, "lua:"); ok {
 			if menu == nil {
 				return
 			}
 
-			state := menu.NewLuaState()
+			common.SetMenuQuery(menu.Name, query)
 
-			if state != nil {
-				functionName := after
+			err := menu.WithLuaState(func(state *lua.LState) error {
+				table := state.NewTable()
 
-				if err := state.CallByParam(lua.P{
-					Fn:      state.GetGlobal(functionName),
+				for k, v := range inputs {
+					table.RawSetString(k, lua.LString(v))
+				}
+
+				return state.CallByParam(lua.P{
+					Fn:      state.GetGlobal(after),
 					NRet:    0,
 					Protect: true,
-				}, lua.LString(e.Value), lua.LString(args)); err != nil {
-					slog.Error(Name, "lua function call", err, "function", functionName)
-				}
+				}, lua.LString(e.Value), lua.LString(args), table)
+			})
+			if err != nil {
+				slog.Error(Name, "lua function call", err, "function", after, "menu", menu.Name)
+				return
+			}
 
-				if menu.History {
-					h.Save(query, identifier)
-				}
-			} else {
-				slog.Error(Name, "no lua state available for menu", menu.Name)
+			if menu.History {
+				h.Save(query, identifier)
 			}
+
 			return
 		}
 
+		values := map[string]string{
+			"VALUE":      e.Value,
+			"ARGS":       args,
+			"QUERY":      query,
+			"TEXT":       e.Text,
+			"SUBTEXT":    e.Subtext,
+			"IDENTIFIER": identifier,
+		}
+
+		placeholders, env := common.InputPlaceholders(fields, inputs)
+
+		maps.Copy(values, placeholders)
+
 		pipe := false
 
-		if strings.Contains(run, "%CLIPBOARD%") {
+		if common.UsesPlaceholder(run, "CLIPBOARD") {
 			clipboard := common.ClipboardText()
 
 			if clipboard == "" {
@@ -175,16 +229,12 @@ func Activate(single bool, identifier, action string, query string, args string,
 				return
 			}
 
-			run = strings.ReplaceAll(run, "%CLIPBOARD%", clipboard)
-		} else {
-			if !strings.Contains(run, "%VALUE%") {
-				pipe = true
-			} else {
-				run = strings.ReplaceAll(run, "%VALUE%", e.Value)
-			}
+			values["CLIPBOARD"] = clipboard
+		} else if !common.UsesPlaceholder(run, "VALUE") {
+			pipe = true
 		}
 
-		run = strings.ReplaceAll(run, "%ARGS%", args)
+		run = common.Expand(run, values)
 
 		if terminal {
 			run = common.WrapWithTerminal(run)
@@ -196,6 +246,10 @@ func Activate(single bool, identifier, action string, query string, args string,
 			Setsid: true,
 		}
 
+		if len(env) != 0 {
+			cmd.Env = append(os.Environ(), env...)
+		}
+
 		if pipe && e.Value != "" {
 			cmd.Stdin = strings.NewReader(e.Value)
 		}
@@ -213,15 +267,16 @@ func Activate(single bool, identifier, action string, query string, args string,
 			h.Save(query, identifier)
 		}
 
-		if slices.Contains(menu.AsyncActions, action) {
+		if menu != nil && slices.Contains(menu.AsyncActions, action) {
 			updated := itemToEntry(format, query, conn, menu.Actions, menu.NamePretty, single, menu.Icon, &e)
 			handlers.UpdateItem(format, query, conn, updated)
-
 		}
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, format uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, format uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(common.MenuConfigLoaded.Matcher)
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 	menu := ""
@@ -235,17 +290,40 @@ func Query(conn net.Conn, query string, single bool, exact bool, format uint8) [
 		query = split[1]
 	}
 
-	for _, v := range common.Menus {
-		if menu != "" && v.Name != menu {
+	menus := common.AllMenus()
+
+	if d := common.GetDmenu(menu); d != nil {
+		menus = []*common.Menu{d}
+	}
+
+	for _, v := range menus {
+		if menu != "" && v.Name != menu || !v.Visible() {
 			continue
 		}
 
-		if v.IsLua && (len(v.Entries) == 0 || !v.Cache) {
-			v.CreateLuaEntries()
+		if v.Dynamic() && (len(v.CurrentEntries()) == 0 || !v.Cache) {
+			common.SetMenuQuery(v.Name, query)
+
+			if v.Async {
+				v.RefreshEntries()
+
+				if err := v.LastError(); err != nil {
+					entries = append(entries, errorItem(v, err))
+				}
+			} else if err := v.CreateEntries(); err != nil {
+				entries = append(entries, errorItem(v, err))
+			}
 		}
 
-		for k, me := range v.Entries {
-			e := itemToEntry(format, query, conn, v.Actions, v.NamePretty, single, v.Icon, &v.Entries[k])
+		items := v.CurrentEntries()
+
+		for k, me := range items {
+			if !v.EntryVisible(&me) {
+				continue
+			}
+
+			e := itemToEntry(format, query, conn, v.Actions, v.NamePretty, single, v.Icon, &items[k])
+			e.Inputs = toInputs(v.ActionInputs(&items[k]), e.Actions)
 
 			if v.FixedOrder {
 				e.Score = 1_000_000 - int32(k)
@@ -257,10 +335,10 @@ func Query(conn net.Conn, query string, single bool, exact bool, format uint8) [
 				}
 
 				if v.SearchName {
-					me.Keywords = append(me.Keywords, me.Menu)
+					me.Keywords = append(slices.Clip(me.Keywords), me.Menu)
 				}
 
-				_, e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start, _ = calcScore(query, me, exact)
+				_, e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start, _ = calcScore(query, me, matcher)
 			}
 
 			var usageScore int32
@@ -288,6 +366,33 @@ func Query(conn net.Conn, query string, single bool, exact bool, format uint8) [
 	return entries
 }
 
+// errorItem shows a failed Lua call or generator in place of the menu's entries, so frontends don't silently show nothing.
+func errorItem(menu *common.Menu, err error) *pb.QueryResponse_Item {
+	return &pb.QueryResponse_Item{
+		Identifier: fmt.Sprintf("%s:error", menu.Name),
+		Text:       err.Error(),
+		Subtext:    fmt.Sprintf("%s: error", menu.NamePretty),
+		Provider:   fmt.Sprintf("%s:%s", Name, menu.Name),
+		Icon:       "dialog-error",
+		State:      []string{"error"},
+		Score:      1_000_001,
+		Type:       pb.QueryResponse_REGULAR,
+	}
+}
+
+// Health reports Lua and generated menus whose last call failed.
+func Health() error {
+	errs := []error{}
+
+	for _, v := range common.AllMenus() {
+		if err := v.LastError(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", v.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 func Icon() string {
 	return ""
 }
@@ -299,18 +404,77 @@ func HideFromProviderlist() bool {
 func State(provider string) *pb.ProviderStateResponse {
 	menu := strings.Split(provider, ":")[1]
 
-	if val, ok := common.Menus[menu]; ok {
+	if val, ok := common.GetMenu(menu); ok {
+		res := &pb.ProviderStateResponse{}
+
 		if val.Parent != "" {
-			return &pb.ProviderStateResponse{
-				Actions: []string{ActionGoParent},
-			}
+			res.Actions = []string{ActionGoParent}
 		}
+
+		res.Inputs = toInputs(val.ActionInputs(nil), nil)
+
+		return res
 	}
 
 	return &pb.ProviderStateResponse{}
 }
 
-func calcScore(q string, d common.Entry, exact bool) (string, int32, []int32, int32, bool) {
+// inputAction maps the action of an activation to the key of its inputs. The menu's default action uses "default".
+func inputAction(action string) string {
+	if action == ActionDefault || action == "" {
+		return "default"
+	}
+
+	return action
+}
+
+// toInputs converts the inputs of the given actions, or all if actions is nil.
+func toInputs(inputs map[string][]common.Input, actions []string) []*pb.ActionInputs {
+	res := []*pb.ActionInputs{}
+
+	keys := slices.Sorted(maps.Keys(inputs))
+
+	for _, k := range keys {
+		action := k
+
+		if action == "default" {
+			action = ActionDefault
+		}
+
+		if actions != nil && !slices.Contains(actions, action) {
+			continue
+		}
+
+		a := &pb.ActionInputs{Action: action}
+
+		for _, v := range inputs[k] {
+			i := &pb.Input{
+				Name:     v.Name,
+				Label:    v.Label,
+				Type:     v.Type,
+				Choices:  v.Choices,
+				Default:  v.Default,
+				Required: v.Required,
+			}
+
+			if i.Label == "" {
+				i.Label = v.Name
+			}
+
+			if i.Type == "" {
+				i.Type = "text"
+			}
+
+			a.Inputs = append(a.Inputs, i)
+		}
+
+		res = append(res, a)
+	}
+
+	return res
+}
+
+func calcScore(q string, d common.Entry, matcher common.Matcher) (string, int32, []int32, int32, bool) {
 	var scoreRes int32
 	var posRes []int32
 	var startRes int32
@@ -321,7 +485,7 @@ func calcScore(q string, d common.Entry, exact bool) (string, int32, []int32, in
 	toSearch = append(toSearch, d.Keywords...)
 
 	for k, v := range toSearch {
-		score, pos, start := common.FuzzyScore(q, v, exact)
+		score, pos, start := common.MatchScore(q, v, matcher)
 
 		if score > scoreRes {
 			scoreRes = score
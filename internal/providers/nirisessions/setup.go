@@ -22,6 +22,7 @@ import (
 var (
 	Name       = "nirisessions"
 	NamePretty = "Niri Sessions"
+	ABIVersion = common.ProviderABIVersion
 	config     *Config
 )
 
@@ -207,7 +208,9 @@ func goWorkspaceDown() {
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
@@ -222,7 +225,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 		}
 
 		if query != "" {
-			score, positions, start := common.FuzzyScore(query, v.Name, exact)
+			score, positions, start := common.MatchScore(query, v.Name, matcher)
 
 			e.Score = score
 			e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
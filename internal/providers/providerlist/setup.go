@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/abenz1267/elephant/v2/internal/comm/handlers"
 	"github.com/abenz1267/elephant/v2/internal/providers"
 	"github.com/abenz1267/elephant/v2/internal/util"
 	"github.com/abenz1267/elephant/v2/pkg/common"
@@ -18,12 +19,18 @@ import (
 var (
 	Name       = "providerlist"
 	NamePretty = "Providerlist"
+	ABIVersion = common.ProviderABIVersion
 	config     *Config
 )
 
 //go:embed README.md
 var readme string
 
+const (
+	ActionEnableIncognito  = "enable_incognito"
+	ActionDisableIncognito = "disable_incognito"
+)
+
 type Config struct {
 	common.Config `koanf:",squash"`
 	Hidden        []string `koanf:"hidden" desc:"hidden providers" default:"<empty>"`
@@ -56,22 +63,34 @@ func PrintDoc() {
 }
 
 func Activate(single bool, identifier, action string, query string, args string, format uint8, conn net.Conn) {
+	switch action {
+	case ActionEnableIncognito:
+		common.SetIncognito(true)
+	case ActionDisableIncognito:
+		common.SetIncognito(false)
+	default:
+		return
+	}
+
+	handlers.ProviderUpdated <- Name
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
-	for _, v := range providers.Providers {
+	for _, v := range providers.All() {
 		if *v.Name == Name || v.HideFromProviderlist() {
 			continue
 		}
 
 		if *v.Name == "menus" {
-			for _, v := range common.Menus {
+			for _, v := range common.AllMenus() {
 				identifier := fmt.Sprintf("%s:%s", "menus", v.Name)
 
-				if slices.Contains(config.Hidden, identifier) || v.HideFromProviderlist {
+				if slices.Contains(config.Hidden, identifier) || v.HideFromProviderlist || !v.Visible() {
 					continue
 				}
 
@@ -90,10 +109,10 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 						Field: "text",
 					}
 
-					e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
+					e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.MatchScore(query, e.Text, matcher)
 
 					for _, v := range v.Keywords {
-						score, positions, start := common.FuzzyScore(query, v, exact)
+						score, positions, start := common.MatchScore(query, v, matcher)
 
 						if score > e.Score {
 							e.Score = score
@@ -126,7 +145,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 					Field: "text",
 				}
 
-				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
+				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.MatchScore(query, e.Text, matcher)
 			}
 
 			if e.Score > config.MinScore || query == "" {
@@ -161,5 +180,15 @@ func HideFromProviderlist() bool {
 }
 
 func State(provider string) *pb.ProviderStateResponse {
-	return &pb.ProviderStateResponse{}
+	if common.Incognito() {
+		return &pb.ProviderStateResponse{
+			States:  []string{"incognito"},
+			Actions: []string{ActionDisableIncognito},
+		}
+	}
+
+	return &pb.ProviderStateResponse{
+		States:  []string{"recording"},
+		Actions: []string{ActionEnableIncognito},
+	}
 }
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abenz1267/elephant/v2/internal/providers"
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	"github.com/abenz1267/elephant/v2/pkg/pb/pb"
+)
+
+// TestQueryLazyProvider lists a lazy provider, whose config is nil until it is set up.
+func TestQueryLazyProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("ELEPHANT_DEV", "true")
+
+	if err := os.MkdirAll(filepath.Join(dir, "elephant"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "elephant", "lazytest.toml"), []byte("lazy = true\nicon = \"lazy-icon\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var lazyConfig *common.Config
+
+	name, pretty := "lazytest", "Lazy Test"
+
+	providers.Register(providers.Provider{
+		Name:                 &name,
+		NamePretty:           &pretty,
+		Available:            func() bool { return true },
+		PrintDoc:             func() {},
+		Setup:                func() { lazyConfig = &common.Config{Icon: "set-up"} },
+		Icon:                 func() string { return lazyConfig.Icon },
+		HideFromProviderlist: func() bool { return lazyConfig.HideFromProviderlist },
+		State:                func(string) *pb.ProviderStateResponse { return &pb.ProviderStateResponse{} },
+		Activate:             func(bool, string, string, string, string, uint8, net.Conn) {},
+		Query: func(net.Conn, string, bool, common.Matcher, uint8) []*pb.QueryResponse_Item {
+			return nil
+		},
+	})
+
+	common.LoadGlobalConfig()
+	providers.Load(true)
+	Setup()
+
+	var found *pb.QueryResponse_Item
+
+	for _, v := range Query(nil, "", false, common.MatcherDefault, 0) {
+		if v.Identifier == name {
+			found = v
+		}
+	}
+
+	if found == nil {
+		t.Fatal("lazy provider isn't listed")
+	}
+
+	if found.Icon != "lazy-icon" {
+		t.Errorf("icon = %q, want the configured lazy-icon", found.Icon)
+	}
+
+	if lazyConfig != nil {
+		t.Error("listing the provider ran its setup")
+	}
+}
@@ -0,0 +1,9 @@
+package providers
+
+// builtin holds providers compiled into the binary, see internal/builtin.
+var builtin = make(map[string]Provider)
+
+// Register adds a provider compiled into the binary. It has to be called before Load, usually from init.
+func Register(p Provider) {
+	builtin[*p.Name] = p
+}
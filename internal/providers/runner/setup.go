@@ -27,6 +27,7 @@ import (
 var (
 	Name       = "runner"
 	NamePretty = "Runner"
+	ABIVersion = common.ProviderABIVersion
 )
 
 //go:embed README.md
@@ -195,7 +196,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	entries := []*pb.QueryResponse_Item{}
 
 	for _, v := range items {
@@ -215,8 +218,8 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 			var positions []int32
 			var start int32
 
-			score, positions, start = common.FuzzyScore(query, v.Bin, exact)
-			s2, p2, ss2 := common.FuzzyScore(query, v.Alias, exact)
+			score, positions, start = common.MatchScore(query, v.Bin, matcher)
+			s2, p2, ss2 := common.MatchScore(query, v.Alias, matcher)
 
 			if s2 > score {
 				e.Text = v.Alias
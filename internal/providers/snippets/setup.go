@@ -6,7 +6,6 @@ import (
 	"net"
 	"os/exec"
 	"strconv"
-	"strings"
 	"time"
 
 	_ "embed"
@@ -19,6 +18,7 @@ import (
 var (
 	Name       = "snippets"
 	NamePretty = "Snippets"
+	ABIVersion = common.ProviderABIVersion
 	config     *Config
 )
 
@@ -31,7 +31,7 @@ const (
 
 type Config struct {
 	common.Config `koanf:",squash"`
-	Command       string    `koanf:"command" desc:"default command to be executed. supports %VALUE%." default:"wtype %CONTENT%"`
+	Command       string    `koanf:"command" desc:"default command to be executed. supports %CONTENT% and the placeholders of other commands." default:"wtype %CONTENT|shell%"`
 	Snippets      []Snippet `koanf:"snippets" desc:"available snippets" default:""`
 	Delay         int       `koanf:"delay" desc:"delay in ms before executing command to avoid potential focus issues" default:"100"`
 }
@@ -48,7 +48,7 @@ func Setup() {
 			Icon:     "insert-text",
 			MinScore: 50,
 		},
-		Command: "wtype %CONTENT%",
+		Command: "wtype %CONTENT|shell%",
 		Delay:   100,
 	}
 
@@ -75,7 +75,10 @@ func Activate(single bool, identifier, action string, query string, args string,
 	i, _ := strconv.Atoi(identifier)
 	s := config.Snippets[i]
 
-	toRun := strings.ReplaceAll(config.Command, "%CONTENT%", s.Content)
+	toRun := common.Expand(config.Command, map[string]string{
+		"CONTENT": s.Content,
+		"QUERY":   query,
+	})
 	cmd := exec.Command("sh", "-c", toRun)
 
 	err := cmd.Start()
@@ -88,7 +91,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
@@ -105,7 +110,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 		}
 
 		if query != "" {
-			score, positions, start, found := calcScore(query, v, exact)
+			score, positions, start, found := calcScore(query, v, matcher)
 
 			if found {
 				e.Score = score
@@ -127,7 +132,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 	return entries
 }
 
-func calcScore(q string, d Snippet, exact bool) (int32, []int32, int32, bool) {
+func calcScore(q string, d Snippet, matcher common.Matcher) (int32, []int32, int32, bool) {
 	var scoreRes int32
 	var posRes []int32
 	var startRes int32
@@ -136,7 +141,7 @@ func calcScore(q string, d Snippet, exact bool) (int32, []int32, int32, bool) {
 	toSearch = append(toSearch, d.Keywords...)
 
 	for _, v := range toSearch {
-		score, pos, start := common.FuzzyScore(q, v, exact)
+		score, pos, start := common.MatchScore(q, v, matcher)
 
 		if score > scoreRes {
 			scoreRes = score
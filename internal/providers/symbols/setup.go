@@ -20,6 +20,7 @@ import (
 var (
 	Name       = "symbols"
 	NamePretty = "Symbols/Emojis"
+	ABIVersion = common.ProviderABIVersion
 	h          = history.Load(Name)
 )
 
@@ -113,7 +114,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
@@ -129,7 +132,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 			var bestStart int32
 
 			for _, m := range v.Searchable {
-				score, positions, start := common.FuzzyScore(query, m, exact)
+				score, positions, start := common.MatchScore(query, m, matcher)
 
 				if score > bestScore {
 					bestScore = score
@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/abenz1267/elephant/v2/internal/comm/handlers"
@@ -27,7 +28,8 @@ import (
 var (
 	Name       = "todo"
 	NamePretty = "Todo List"
-	config     *Config
+	ABIVersion = common.ProviderABIVersion
+	current    atomic.Pointer[Config]
 	items      = []Item{}
 	parser     *naturaltime.Parser
 	isGit      bool
@@ -123,6 +125,8 @@ func (i Item) toCSVRow() string {
 }
 
 func saveItems() {
+	config := getConfig()
+
 	f := common.CacheFile(fmt.Sprintf("%s.csv", Name))
 
 	if config.Location != "" {
@@ -161,6 +165,8 @@ func saveItems() {
 }
 
 func (i *Item) fromQuery(query string) {
+	config := getConfig()
+
 	category := ""
 
 	for _, v := range config.Categories {
@@ -206,7 +212,36 @@ func Setup() {
 		panic(err)
 	}
 
-	config = &Config{
+	loadConfig()
+
+	config := getConfig()
+
+	if strings.HasPrefix(config.Location, "https://") {
+		isGit = true
+	}
+
+	ec := common.GetElephantConfig()
+
+	if !ec.GitOnDemand && isGit {
+		common.SetupGit(Name, config)
+		loadItems()
+	}
+
+	if !isGit {
+		if !migrateGOBtoCSV() {
+			loadItems()
+		}
+	}
+
+	go notify()
+}
+
+func getConfig() *Config {
+	return current.Load()
+}
+
+func loadConfig() {
+	config := &Config{
 		Config: common.Config{
 			Icon:     "checkbox-checked",
 			MinScore: 20,
@@ -227,24 +262,19 @@ func Setup() {
 		NamePretty = config.NamePretty
 	}
 
-	if strings.HasPrefix(config.Location, "https://") {
-		isGit = true
-	}
-
-	ec := common.GetElephantConfig()
-
-	if !ec.GitOnDemand && isGit {
-		common.SetupGit(Name, config)
-		loadItems()
+	if old := getConfig(); old != nil {
+		config.Location = old.Location
+		config.w = old.w
+		config.r = old.r
 	}
 
-	if !isGit {
-		if !migrateGOBtoCSV() {
-			loadItems()
-		}
-	}
+	current.Store(config)
+}
 
-	go notify()
+// Reload swaps in the new config. The location and its git checkout are kept,
+// moving the todo list needs a restart.
+func Reload() {
+	loadConfig()
 }
 
 func Available() bool {
@@ -257,6 +287,8 @@ func notify() {
 		nextMinute := now.Add(time.Minute)
 		time.Sleep(time.Until(nextMinute))
 
+		config := getConfig()
+
 		now = time.Now().Truncate(time.Minute)
 
 		hasNotification := false
@@ -316,6 +348,8 @@ func PrintDoc() {
 }
 
 func Activate(single bool, identifier, action string, query string, args string, format uint8, conn net.Conn) {
+	config := getConfig()
+
 	i, _ := strconv.Atoi(identifier)
 
 	switch action {
@@ -446,6 +480,8 @@ var (
 )
 
 func loadItems() {
+	config := getConfig()
+
 	loadMu.Lock()
 	defer loadMu.Unlock()
 
@@ -520,7 +556,11 @@ func loadItems() {
 	loaded = true
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	config := getConfig()
+
+	matcher = matcher.Or(config.Matcher)
+
 	if isGit && config.r == nil {
 		common.SetupGit(Name, config)
 		loadItems()
@@ -593,7 +633,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 			e := itemToEntry(urgent, i, v)
 
 			if query != "" {
-				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.FuzzyScore(query, e.Text, exact)
+				e.Score, e.Fuzzyinfo.Positions, e.Fuzzyinfo.Start = common.MatchScore(query, e.Text, matcher)
 			}
 
 			if slices.Contains(e.State, StateActive) && query == "" {
@@ -651,11 +691,11 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 }
 
 func Icon() string {
-	return config.Icon
+	return getConfig().Icon
 }
 
 func HideFromProviderlist() bool {
-	return config.HideFromProviderlist
+	return getConfig().HideFromProviderlist
 }
 
 func State(provider string) *pb.ProviderStateResponse {
@@ -685,6 +725,8 @@ func State(provider string) *pb.ProviderStateResponse {
 }
 
 func itemToEntry(urgent time.Time, i int, v Item) *pb.QueryResponse_Item {
+	config := getConfig()
+
 	e := &pb.QueryResponse_Item{}
 
 	if v.State == StateDone {
@@ -20,6 +20,7 @@ import (
 var (
 	Name       = "unicode"
 	NamePretty = "Unicode"
+	ABIVersion = common.ProviderABIVersion
 	h          = history.Load(Name)
 )
 
@@ -38,8 +39,10 @@ type Config struct {
 }
 
 var (
-	config  *Config
-	symbols = make(map[string]string)
+	config     *Config
+	symbols    = make(map[string]string)
+	corpus     *common.Corpus
+	corpusKeys []string
 )
 
 func Setup() {
@@ -71,6 +74,14 @@ func Setup() {
 		symbols[fields[1]] = fields[0]
 	}
 
+	corpus = common.NewCorpus(len(symbols))
+	corpusKeys = make([]string, 0, len(symbols))
+
+	for k := range symbols {
+		corpus.Add(k)
+		corpusKeys = append(corpusKeys, k)
+	}
+
 	slog.Info(Name, "loaded", time.Since(start))
 }
 
@@ -120,43 +131,19 @@ func Activate(single bool, identifier, action string, query string, args string,
 	}
 }
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 	entries := []*pb.QueryResponse_Item{}
 
-	for k, v := range symbols {
-		score, positions, start := common.FuzzyScore(query, k, exact)
-
-		var usageScore int32
-		if config.History {
-			if score > config.MinScore || query == "" && config.HistoryWhenEmpty {
-				usageScore = h.CalcUsageScore(query, k)
-				score = score + usageScore
-			}
+	if query != "" {
+		for _, res := range corpus.Score(query, matcher, config.MinScore, 0) {
+			entries = append(entries, symbolToEntry(query, corpusKeys[res.Index], res.Score, res.Positions, res.Start))
 		}
-
-		if usageScore != 0 || score > config.MinScore || query == "" {
-			state := []string{}
-
-			if usageScore != 0 {
-				state = append(state, "history")
-			}
-
-			entries = append(entries, &pb.QueryResponse_Item{
-				Identifier: k,
-				Score:      score,
-				State:      state,
-				Text:       k,
-				Icon:       v,
-				Provider:   Name,
-				Actions:    []string{ActionRunCmd},
-				Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
-					Start:     start,
-					Field:     "text",
-					Positions: positions,
-				},
-				Type: pb.QueryResponse_REGULAR,
-			})
+	} else {
+		for k := range symbols {
+			entries = append(entries, symbolToEntry(query, k, 0, nil, 0))
 		}
 	}
 
@@ -164,6 +151,38 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 	return entries
 }
 
+func symbolToEntry(query, k string, score int32, positions []int32, start int32) *pb.QueryResponse_Item {
+	var usageScore int32
+	if config.History {
+		if score > config.MinScore || query == "" && config.HistoryWhenEmpty {
+			usageScore = h.CalcUsageScore(query, k)
+			score = score + usageScore
+		}
+	}
+
+	state := []string{}
+
+	if usageScore != 0 {
+		state = append(state, "history")
+	}
+
+	return &pb.QueryResponse_Item{
+		Identifier: k,
+		Score:      score,
+		State:      state,
+		Text:       k,
+		Icon:       symbols[k],
+		Provider:   Name,
+		Actions:    []string{ActionRunCmd},
+		Fuzzyinfo: &pb.QueryResponse_Item_FuzzyInfo{
+			Start:     start,
+			Field:     "text",
+			Positions: positions,
+		},
+		Type: pb.QueryResponse_REGULAR,
+	}
+}
+
 func Icon() string {
 	return config.Icon
 }
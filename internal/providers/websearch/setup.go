@@ -23,6 +23,7 @@ import (
 var (
 	Name       = "websearch"
 	NamePretty = "Websearch"
+	ABIVersion = common.ProviderABIVersion
 	config     *Config
 	prefixes   = make(map[string]int)
 	h          = history.Load(Name)
@@ -135,19 +136,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 			args = query
 		}
 
-		q := ""
-
-		if strings.Contains(config.Engines[i].URL, "%CLIPBOARD%") {
-			clipboard := common.ClipboardText()
-
-			if clipboard == "" {
-				slog.Error(Name, "activate", "empty clipbpoard")
-				return
-			}
-
-			q = strings.ReplaceAll(os.ExpandEnv(config.Engines[i].URL), "%CLIPBOARD%", url.QueryEscape(clipboard))
-		} else {
-			q = strings.ReplaceAll(os.ExpandEnv(config.Engines[i].URL), "%TERM%", url.QueryEscape(strings.TrimSpace(args)))
+		q, ok := expandURL(os.ExpandEnv(config.Engines[i].URL), query, args)
+		if !ok {
+			return
 		}
 
 		run(query, identifier, q)
@@ -166,21 +157,34 @@ func Activate(single bool, identifier, action string, query string, args string,
 			}
 		}
 
-		if strings.Contains(q, "%CLIPBOARD%") {
-			clipboard := common.ClipboardText()
+		q, ok := expandURL(q, query, query)
+		if !ok {
+			return
+		}
 
-			if clipboard == "" {
-				slog.Error(Name, "activate", "empty clipbpoard")
-				return
-			}
+		run(query, identifier, q)
+	}
+}
+
+// expandURL fills in the search term and the clipboard, both url escaped. See common.Expand for other placeholders.
+func expandURL(q, query, term string) (string, bool) {
+	values := map[string]string{
+		"TERM":  url.QueryEscape(strings.TrimSpace(term)),
+		"QUERY": query,
+	}
 
-			q = strings.ReplaceAll(q, "%CLIPBOARD%", url.QueryEscape(clipboard))
-		} else {
-			q = strings.ReplaceAll(q, "%TERM%", url.QueryEscape(strings.TrimSpace(query)))
+	if common.UsesPlaceholder(q, "CLIPBOARD") {
+		clipboard := common.ClipboardText()
+
+		if clipboard == "" {
+			slog.Error(Name, "activate", "empty clipbpoard")
+			return "", false
 		}
 
-		run(query, identifier, q)
+		values["CLIPBOARD"] = url.QueryEscape(clipboard)
 	}
+
+	return common.Expand(q, values), true
 }
 
 func run(query, identifier, q string) {
@@ -204,7 +208,9 @@ func run(query, identifier, q string) {
 	}
 }
 
-func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, single bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	entries := []*pb.QueryResponse_Item{}
 
 	prefix := ""
@@ -254,7 +260,7 @@ func Query(conn net.Conn, query string, single bool, exact bool, _ uint8) []*pb.
 				}
 
 				if query != "" {
-					score, pos, start := common.FuzzyScore(query, v.Name, exact)
+					score, pos, start := common.MatchScore(query, v.Name, matcher)
 
 					e.Score = score
 					e.Fuzzyinfo = &pb.QueryResponse_Item_FuzzyInfo{
@@ -27,6 +27,7 @@ import (
 var (
 	Name       = "windows"
 	NamePretty = "Windows"
+	ABIVersion = common.ProviderABIVersion
 )
 
 var (
@@ -92,7 +93,9 @@ func Activate(single bool, identifier, action string, query string, args string,
 	wlr.Activate(wl.ProxyId(i))
 }
 
-func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.QueryResponse_Item {
+func Query(conn net.Conn, query string, _ bool, matcher common.Matcher, _ uint8) []*pb.QueryResponse_Item {
+	matcher = matcher.Or(config.Matcher)
+
 	start := time.Now()
 
 	entries := []*pb.QueryResponse_Item{}
@@ -116,7 +119,7 @@ func Query(conn net.Conn, query string, _ bool, exact bool, _ uint8) []*pb.Query
 		mu.RUnlock()
 
 		if query != "" {
-			matched, score, pos, start, ok := calcScore(query, window, exact)
+			matched, score, pos, start, ok := calcScore(query, window, matcher)
 
 			if ok {
 				field := "text"
@@ -156,7 +159,7 @@ func State(provider string) *pb.ProviderStateResponse {
 	return &pb.ProviderStateResponse{}
 }
 
-func calcScore(q string, d *wlr.Window, exact bool) (string, int32, []int32, int32, bool) {
+func calcScore(q string, d *wlr.Window, matcher common.Matcher) (string, int32, []int32, int32, bool) {
 	var scoreRes int32
 	var posRes []int32
 	var startRes int32
@@ -165,7 +168,7 @@ func calcScore(q string, d *wlr.Window, exact bool) (string, int32, []int32, int
 	toSearch := []string{d.Title, d.AppID}
 
 	for _, v := range toSearch {
-		score, pos, start := common.FuzzyScore(q, v, exact)
+		score, pos, start := common.MatchScore(q, v, matcher)
 
 		if score > scoreRes {
 			scoreRes = score
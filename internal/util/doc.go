@@ -30,11 +30,7 @@ func GenerateDoc(provider string) {
 		fmt.Println("## Provider Configuration")
 	}
 	
-	p := []providers.Provider{}
-
-	for _, v := range providers.Providers {
-		p = append(p, v)
-	}
+	p := providers.All()
 
 	slices.SortFunc(p, func(a, b providers.Provider) int {
 		return strings.Compare(*a.NamePretty, *b.NamePretty)
@@ -113,6 +109,17 @@ func printStructDesc(c any) {
 					nestedStructs = append(nestedStructs, elemType)
 				}
 			}
+
+			if field.Type.Kind() == reflect.Map && field.Type.Elem().Kind() == reflect.Slice {
+				elemType := field.Type.Elem().Elem()
+				if elemType.Kind() == reflect.Struct {
+					nestedStructs = append(nestedStructs, elemType)
+				}
+			}
+
+			if field.Type.Kind() == reflect.Struct {
+				nestedStructs = append(nestedStructs, field.Type)
+			}
 		}
 	}
 
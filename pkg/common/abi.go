@@ -0,0 +1,8 @@
+package common
+
+// ProviderABIVersion has to be exported by every provider plugin as
+//
+//	var ABIVersion = common.ProviderABIVersion
+//
+// It is increased whenever the required provider symbols or their signatures change.
+const ProviderABIVersion = 1
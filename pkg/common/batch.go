@@ -0,0 +1,144 @@
+package common
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/junegunn/fzf/src/util"
+)
+
+// same slab sizes fzf uses for its matchers.
+const (
+	slab16Size = 100 * 1024
+	slab32Size = 2048
+)
+
+var slabs = sync.Pool{
+	New: func() any {
+		return util.MakeSlab(slab16Size, slab32Size)
+	},
+}
+
+// Corpus holds pre-tokenised items for batch scoring. Every item can have
+// multiple fields, f.e. name and description, the best scoring field wins.
+type Corpus struct {
+	items [][]matchTarget
+}
+
+type BatchResult struct {
+	Index     int
+	Field     int
+	Score     int32
+	Positions []int32
+	Start     int32
+}
+
+func NewCorpus(size int) *Corpus {
+	return &Corpus{
+		items: make([][]matchTarget, 0, size),
+	}
+}
+
+// Add adds an item to the corpus and returns its index. Not safe for concurrent use.
+func (c *Corpus) Add(fields ...string) int {
+	item := make([]matchTarget, len(fields))
+
+	for i, v := range fields {
+		item[i] = newMatchTarget(v)
+	}
+
+	c.items = append(c.items, item)
+
+	return len(c.items) - 1
+}
+
+func (c *Corpus) Len() int {
+	return len(c.items)
+}
+
+// Score scores the query against every item in parallel and returns matches
+// with a score above minScore, sorted by score. If k > 0 only the top k are
+// returned. Positions are only calculated for the returned results.
+func (c *Corpus) Score(query string, matcher Matcher, minScore int32, k int) []BatchResult {
+	if len(c.items) == 0 {
+		return nil
+	}
+
+	p := newMatchPattern(query)
+
+	workers := min(runtime.NumCPU(), len(c.items))
+	chunk := (len(c.items) + workers - 1) / workers
+
+	partials := make([][]BatchResult, workers)
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		from := w * chunk
+		to := min(from+chunk, len(c.items))
+
+		if from >= to {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(w, from, to int) {
+			defer wg.Done()
+
+			slab := slabs.Get().(*util.Slab)
+			defer slabs.Put(slab)
+
+			res := []BatchResult{}
+
+			for i := from; i < to; i++ {
+				best := BatchResult{Index: i, Field: -1}
+
+				for f := range c.items[i] {
+					score, _, start := match(&p, &c.items[i][f], matcher, false, slab)
+
+					if start >= 0 && (best.Field == -1 || score > best.Score) {
+						best.Score = score
+						best.Field = f
+						best.Start = start
+					}
+				}
+
+				if best.Field != -1 && best.Score > minScore {
+					res = append(res, best)
+				}
+			}
+
+			if k > 0 && len(res) > k {
+				slices.SortFunc(res, compareBatchResults)
+				res = res[:k]
+			}
+
+			partials[w] = res
+		}(w, from, to)
+	}
+
+	wg.Wait()
+
+	results := slices.Concat(partials...)
+	slices.SortFunc(results, compareBatchResults)
+
+	if k > 0 && len(results) > k {
+		results = results[:k]
+	}
+
+	for i, v := range results {
+		_, results[i].Positions, _ = match(&p, &c.items[v.Index][v.Field], matcher, true, nil)
+	}
+
+	return results
+}
+
+func compareBatchResults(a, b BatchResult) int {
+	if a.Score != b.Score {
+		return int(b.Score - a.Score)
+	}
+
+	return a.Index - b.Index
+}
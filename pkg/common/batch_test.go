@@ -0,0 +1,70 @@
+package common
+
+import (
+	"fmt"
+	"testing"
+)
+
+func benchCorpusTargets() []string {
+	words := []string{"lib", "python", "go", "rust", "qt", "gtk", "wayland", "firefox", "font", "plugin", "docs", "git", "utils", "server", "client"}
+	targets := make([]string, 0, 30_000)
+
+	for i := range 30_000 {
+		targets = append(targets, fmt.Sprintf("%s-%s-%s%d", words[i%len(words)], words[(i/7)%len(words)], words[(i/13)%len(words)], i))
+	}
+
+	return targets
+}
+
+func TestCorpusScoreMatchesMatchScore(t *testing.T) {
+	targets := benchCorpusTargets()[:2000]
+	c := NewCorpus(len(targets))
+
+	for _, v := range targets {
+		c.Add(v)
+	}
+
+	for _, res := range c.Score("wlfox", MatcherFuzzy, 0, 0) {
+		score, positions, start := MatchScore("wlfox", targets[res.Index], MatcherFuzzy)
+
+		if score != res.Score || start != res.Start || fmt.Sprint(positions) != fmt.Sprint(res.Positions) {
+			t.Fatalf("%s: got %d %v %d, want %d %v %d", targets[res.Index], res.Score, res.Positions, res.Start, score, positions, start)
+		}
+	}
+}
+
+func BenchmarkMatchScore(b *testing.B) {
+	targets := benchCorpusTargets()
+
+	for b.Loop() {
+		for _, v := range targets {
+			MatchScore("wlfox", v, MatcherFuzzy)
+		}
+	}
+}
+
+func BenchmarkCorpusScore(b *testing.B) {
+	targets := benchCorpusTargets()
+	c := NewCorpus(len(targets))
+
+	for _, v := range targets {
+		c.Add(v)
+	}
+
+	for b.Loop() {
+		c.Score("wlfox", MatcherFuzzy, 0, 0)
+	}
+}
+
+func BenchmarkCorpusScoreTopK(b *testing.B) {
+	targets := benchCorpusTargets()
+	c := NewCorpus(len(targets))
+
+	for _, v := range targets {
+		c.Add(v)
+	}
+
+	for b.Loop() {
+		c.Score("wlfox", MatcherFuzzy, 0, 50)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/joho/godotenv"
 	"github.com/knadh/koanf/parsers/toml/v2"
@@ -14,10 +15,13 @@ import (
 )
 
 type Config struct {
-	Icon                 string `koanf:"icon" desc:"icon for provider" default:"depends on provider"`
-	NamePretty           string `koanf:"name_pretty" desc:"displayed name for the provider" default:"depends on provider"`
-	MinScore             int32  `koanf:"min_score" desc:"minimum score for items to be displayed" default:"depends on provider"`
-	HideFromProviderlist bool   `koanf:"hide_from_providerlist" desc:"hides a provider from the providerlist provider. provider provider." default:"false"`
+	Icon                 string   `koanf:"icon" desc:"icon for provider" default:"depends on provider"`
+	NamePretty           string   `koanf:"name_pretty" desc:"displayed name for the provider" default:"depends on provider"`
+	MinScore             int32    `koanf:"min_score" desc:"minimum score for items to be displayed" default:"depends on provider"`
+	HideFromProviderlist bool     `koanf:"hide_from_providerlist" desc:"hides a provider from the providerlist provider. provider provider." default:"false"`
+	Matcher              Matcher  `koanf:"matcher" desc:"matching algorithm: fuzzy, exact, prefix, initials, substring, casesensitive" default:"fuzzy"`
+	Lazy                 bool     `koanf:"lazy" desc:"defers the setup until the provider is queried for the first time" default:"false"`
+	HistoryExclude       []string `koanf:"history_exclude" desc:"regular expressions, matching items are never recorded in the history" default:"<empty>"`
 }
 
 type Command struct {
@@ -26,11 +30,19 @@ type Command struct {
 }
 
 type ElephantConfig struct {
-	AutoDetectLaunchPrefix bool      `koanf:"auto_detect_launch_prefix" desc:"automatically detects uwsm, app2unit or systemd-run" default:"true"`
-	OverloadLocalEnv       bool      `koanf:"overload_local_env" desc:"overloads the local env" default:"false"`
-	IgnoredProviders       []string  `koanf:"ignored_providers" desc:"providers to ignore" default:"<empty>"`
-	GitOnDemand            bool      `koanf:"git_on_demand" desc:"sets up git repositories on first query instead of on start" default:"true"`
-	BeforeLoad             []Command `koanf:"before_load" desc:"commands to run before starting to load the providers" default:""`
+	AutoDetectLaunchPrefix bool              `koanf:"auto_detect_launch_prefix" desc:"automatically detects uwsm, app2unit or systemd-run" default:"true"`
+	OverloadLocalEnv       bool              `koanf:"overload_local_env" desc:"overloads the local env" default:"false"`
+	IgnoredProviders       []string          `koanf:"ignored_providers" desc:"providers to ignore" default:"<empty>"`
+	GitOnDemand            bool              `koanf:"git_on_demand" desc:"sets up git repositories on first query instead of on start" default:"true"`
+	AvailabilityInterval   int               `koanf:"availability_interval" desc:"seconds between checking if unavailable providers became available, 0 disables it" default:"10"`
+	BeforeLoad             []Command         `koanf:"before_load" desc:"commands to run before starting to load the providers" default:""`
+	FoldDiacritics         bool              `koanf:"fold_diacritics" desc:"ignore diacritics when matching, f.e. 'cafe' matches 'Café'" default:"true"`
+	Transliterate          []string          `koanf:"transliterate" desc:"built-in transliteration tables used when matching: ligatures, cyrillic, greek" default:"[\"ligatures\"]"`
+	Transliteration        map[string]string `koanf:"transliteration" desc:"custom transliteration table used when matching, f.e. { \"ä\" = \"ae\" }" default:"<empty>"`
+	HistoryHalfLife        float64           `koanf:"history_half_life" desc:"days after which the weight of a history entry is halved" default:"7"`
+	HistoryContextWeight   float64           `koanf:"history_context_weight" desc:"boost for items usually activated at a similar time, app or workspace, 0 disables it" default:"1"`
+	HistoryMaxAge          int               `koanf:"history_max_age" desc:"days after which history entries are deleted, 0 keeps them forever" default:"0"`
+	HistoryMaxEntries      int               `koanf:"history_max_entries" desc:"max amount of items kept in the history of each provider, 0 is unlimited" default:"0"`
 }
 
 var elephantConfig *ElephantConfig
@@ -40,10 +52,17 @@ func LoadGlobalConfig() {
 		AutoDetectLaunchPrefix: true,
 		OverloadLocalEnv:       false,
 		GitOnDemand:            true,
+		AvailabilityInterval:   10,
+		FoldDiacritics:         true,
+		Transliterate:          []string{"ligatures"},
+		HistoryHalfLife:        7,
+		HistoryContextWeight:   1,
 	}
 
 	LoadConfig("elephant", elephantConfig)
 
+	SetupFolding(elephantConfig.FoldDiacritics, elephantConfig.Transliterate, elephantConfig.Transliteration)
+
 	for _, v := range ConfigDirs() {
 		envFile := filepath.Join(v, ".env")
 
@@ -66,11 +85,53 @@ func LoadGlobalConfig() {
 	}
 }
 
+// IsLazy reports whether the provider's setup should be deferred until it is used.
+func IsLazy(provider string) bool {
+	return ProviderSettings(provider).Lazy
+}
+
+var (
+	providerConfigsMut sync.Mutex
+	providerConfigs    = make(map[string]Config)
+)
+
+// base is promoted to provider configs embedding Config, so LoadConfig can keep the common part.
+func (c *Config) base() *Config {
+	return c
+}
+
+// ProviderSettings returns the common part of a provider's config as loaded by its last LoadConfig call.
+// If the provider didn't load its config yet, f.e. because it's lazy, only the common part is loaded.
+func ProviderSettings(provider string) Config {
+	providerConfigsMut.Lock()
+	c, ok := providerConfigs[provider]
+	providerConfigsMut.Unlock()
+
+	if ok {
+		return c
+	}
+
+	LoadConfig(provider, &Config{})
+
+	providerConfigsMut.Lock()
+	defer providerConfigsMut.Unlock()
+
+	return providerConfigs[provider]
+}
+
 func GetElephantConfig() *ElephantConfig {
 	return elephantConfig
 }
 
 func LoadConfig(provider string, config any) {
+	defer func() {
+		if c, ok := config.(interface{ base() *Config }); ok {
+			providerConfigsMut.Lock()
+			providerConfigs[provider] = *c.base()
+			providerConfigsMut.Unlock()
+		}
+	}()
+
 	defaults := koanf.New(".")
 
 	err := defaults.Load(structs.Provider(config, "koanf"), nil)
@@ -104,4 +165,9 @@ func LoadConfig(provider string, config any) {
 		slog.Error(provider, "config", err)
 		os.Exit(1)
 	}
+
+	if c, ok := config.(interface{ base() *Config }); ok && !c.base().Matcher.Valid() {
+		slog.Warn(provider, "config", "unknown matcher, using fuzzy", "matcher", c.base().Matcher)
+		c.base().Matcher = MatcherFuzzy
+	}
 }
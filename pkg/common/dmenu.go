@@ -0,0 +1,76 @@
+package common
+
+import "sync"
+
+// dmenu is a menu created at runtime, f.e. by `elephant dmenu`, waiting for the user to pick an entry.
+type dmenu struct {
+	menu     *Menu
+	selected chan Entry
+}
+
+var (
+	dmenus    = make(map[string]*dmenu)
+	dmenusMut sync.Mutex
+)
+
+// RegisterDmenu makes the menu available until remove is called. It replaces a configured menu with the same name.
+// The picked entry is sent on the returned channel, which is closed if another dmenu with the same name replaces this one.
+func RegisterDmenu(m *Menu) (selected <-chan Entry, remove func()) {
+	for k := range m.Entries {
+		m.identify(&m.Entries[k])
+	}
+
+	d := &dmenu{
+		menu:     m,
+		selected: make(chan Entry, 1),
+	}
+
+	dmenusMut.Lock()
+
+	if prev, ok := dmenus[m.Name]; ok {
+		close(prev.selected)
+	}
+
+	dmenus[m.Name] = d
+
+	dmenusMut.Unlock()
+
+	return d.selected, func() {
+		dmenusMut.Lock()
+		defer dmenusMut.Unlock()
+
+		if dmenus[m.Name] == d {
+			delete(dmenus, m.Name)
+		}
+	}
+}
+
+// GetDmenu returns the registered dmenu with the given name, or nil.
+func GetDmenu(name string) *Menu {
+	dmenusMut.Lock()
+	defer dmenusMut.Unlock()
+
+	if d, ok := dmenus[name]; ok {
+		return d.menu
+	}
+
+	return nil
+}
+
+// SelectDmenu passes the picked entry to the waiting request. It reports false if there's no dmenu with the given name.
+func SelectDmenu(name string, e Entry) bool {
+	dmenusMut.Lock()
+	defer dmenusMut.Unlock()
+
+	d, ok := dmenus[name]
+	if !ok {
+		return false
+	}
+
+	select {
+	case d.selected <- e:
+	default:
+	}
+
+	return true
+}
@@ -0,0 +1,39 @@
+package common
+
+import "testing"
+
+func TestDmenu(t *testing.T) {
+	selected, remove := RegisterDmenu(&Menu{Name: "pick", Entries: []Entry{{Text: "a", Value: "a"}}})
+	defer remove()
+
+	m := GetDmenu("pick")
+	if m == nil || m.Entries[0].Identifier == "" {
+		t.Fatalf("GetDmenu: %v", m)
+	}
+
+	if !SelectDmenu("pick", m.Entries[0]) || SelectDmenu("missing", Entry{}) {
+		t.Fatal("SelectDmenu: wrong result")
+	}
+
+	if e := <-selected; e.Value != "a" {
+		t.Errorf("selected %q, want a", e.Value)
+	}
+
+	_, removeReplaced := RegisterDmenu(&Menu{Name: "pick"})
+
+	if _, ok := <-selected; ok {
+		t.Error("replaced dmenu wasn't closed")
+	}
+
+	remove()
+
+	if GetDmenu("pick") == nil {
+		t.Error("remove deleted the replacing dmenu")
+	}
+
+	removeReplaced()
+
+	if GetDmenu("pick") != nil {
+		t.Error("dmenu wasn't removed")
+	}
+}
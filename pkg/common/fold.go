@@ -0,0 +1,191 @@
+package common
+
+import (
+	"log/slog"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// built-in transliteration tables, only lowercase is listed, uppercase is derived.
+var transliterationTables = map[string]map[rune]string{
+	"ligatures": {
+		'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i", 'ŋ': "ng",
+	},
+	"cyrillic": {
+		'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z", 'и': "i",
+		'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t",
+		'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "",
+		'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi", 'ґ': "g",
+	},
+	"greek": {
+		'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i", 'κ': "k",
+		'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+		'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	},
+}
+
+var (
+	foldDiacritics     = true
+	transliteration    = map[rune]string{}
+	transliterateASCII = false
+)
+
+// SetupFolding configures diacritic folding and transliteration used when matching.
+func SetupFolding(diacritics bool, tables []string, custom map[string]string) {
+	foldDiacritics = diacritics
+	transliteration = map[rune]string{}
+	transliterateASCII = false
+
+	for _, name := range tables {
+		table, ok := transliterationTables[name]
+		if !ok {
+			slog.Error("elephant", "transliteration", "unknown table", "table", name)
+			continue
+		}
+
+		for k, v := range table {
+			addTransliteration(k, v)
+		}
+	}
+
+	for k, v := range custom {
+		r, size := utf8.DecodeRuneInString(k)
+		if r == utf8.RuneError || size != len(k) {
+			slog.Error("elephant", "transliteration", "key must be a single character", "key", k)
+			continue
+		}
+
+		addTransliteration(r, v)
+	}
+}
+
+func addTransliteration(r rune, replacement string) {
+	transliteration[r] = replacement
+
+	if r < utf8.RuneSelf {
+		transliterateASCII = true
+	}
+
+	if upper := unicode.ToUpper(r); upper != r {
+		if _, ok := transliteration[upper]; !ok {
+			first, size := utf8.DecodeRuneInString(replacement)
+
+			if size == 0 {
+				transliteration[upper] = ""
+			} else {
+				transliteration[upper] = string(unicode.ToUpper(first)) + replacement[size:]
+			}
+		}
+	}
+}
+
+// foldRunes strips diacritics and transliterates the given string. The returned
+// mapping holds the index of the original rune for every folded rune, it is nil
+// if nothing had to be folded.
+func foldRunes(s string) ([]rune, []int) {
+	if !needsFolding(s) {
+		return nil, nil
+	}
+
+	folded := make([]rune, 0, len(s))
+	mapping := make([]int, 0, len(s))
+
+	// reused for every rune, so folding doesn't allocate per rune.
+	base := make([]rune, 0, 4)
+	src := make([]byte, 0, utf8.UTFMax)
+
+	i := 0
+
+	for _, r := range s {
+		base = append(base[:0], r)
+
+		if foldDiacritics && r >= utf8.RuneSelf {
+			base = base[:0]
+
+			src = utf8.AppendRune(src[:0], r)
+
+			// the decomposition is read from the normalization tables, runes without one are kept.
+			decomposed := norm.NFD.Properties(src).Decomposition()
+			if decomposed == nil {
+				decomposed = src
+			}
+
+			for k := 0; k < len(decomposed); {
+				d, size := utf8.DecodeRune(decomposed[k:])
+				k += size
+
+				if !unicode.Is(unicode.Mn, d) {
+					base = append(base, d)
+				}
+			}
+		}
+
+		for _, b := range base {
+			replacement, ok := transliteration[b]
+			if !ok {
+				folded = append(folded, b)
+				mapping = append(mapping, i)
+				continue
+			}
+
+			for _, t := range replacement {
+				folded = append(folded, t)
+				mapping = append(mapping, i)
+			}
+		}
+
+		i++
+	}
+
+	return folded, mapping
+}
+
+func foldString(s string) string {
+	folded, _ := foldRunes(s)
+	if folded == nil {
+		return s
+	}
+
+	return string(folded)
+}
+
+func needsFolding(s string) bool {
+	if transliterateASCII {
+		return true
+	}
+
+	if !foldDiacritics && len(transliteration) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unfoldPositions maps positions in the folded string back to the original runes.
+func unfoldPositions(pos []int32, mapping []int) []int32 {
+	res := make([]int32, 0, len(pos))
+
+	for _, p := range pos {
+		if int(p) >= len(mapping) {
+			continue
+		}
+
+		o := int32(mapping[p])
+
+		if len(res) > 0 && res[len(res)-1] == o {
+			continue
+		}
+
+		res = append(res, o)
+	}
+
+	return res
+}
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFoldPositions(t *testing.T) {
+	SetupFolding(true, []string{"cyrillic"}, nil)
+	defer SetupFolding(true, nil, nil)
+
+	tests := []struct {
+		input  string
+		target string
+		want   []int32
+	}{
+		{"cafe", "Le Café", []int32{3, 4, 5, 6}},
+		{"creme", "Crème brûlée", []int32{0, 1, 2, 3, 4}},
+		{"privet", "Привет", []int32{0, 1, 2, 3, 4, 5}},
+		{"borshch", "борщ", []int32{0, 1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		score, pos, _ := MatchScore(tt.input, tt.target, MatcherFuzzy)
+
+		slices.Sort(pos)
+
+		if score <= 0 || !slices.Equal(pos, tt.want) {
+			t.Errorf("MatchScore(%q, %q) = %d %v, want positions %v", tt.input, tt.target, score, pos, tt.want)
+		}
+	}
+}
+
+func TestFoldAllocations(t *testing.T) {
+	SetupFolding(true, nil, nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		foldRunes("àéîõüçñåøæ àéîõüçñåøæ")
+	})
+
+	if allocs > 4 {
+		t.Errorf("got %.0f allocations, want them independent of the number of runes", allocs)
+	}
+}
@@ -2,27 +2,119 @@ package common
 
 import (
 	"slices"
+	"strings"
 	"unicode"
 
 	"github.com/junegunn/fzf/src/algo"
 	"github.com/junegunn/fzf/src/util"
 )
 
+// Matcher selects the algorithm used to match a query against a target.
+type Matcher string
+
+const (
+	MatcherDefault       Matcher = ""
+	MatcherFuzzy         Matcher = "fuzzy"
+	MatcherExact         Matcher = "exact"
+	MatcherPrefix        Matcher = "prefix"
+	MatcherInitials      Matcher = "initials"
+	MatcherSubstring     Matcher = "substring"
+	MatcherCaseSensitive Matcher = "casesensitive"
+)
+
+// scores for the initials matcher, mirroring fzf's scoreMatch, bonusBoundary and scoreGapStart.
+const (
+	initialsScoreMatch = 16
+	initialsBonus      = 8
+	initialsGapPenalty = 3
+)
+
 func init() {
 	algo.Init("default")
 }
 
+// Valid reports whether m is a known matcher, unset counts as valid.
+func (m Matcher) Valid() bool {
+	switch m {
+	case MatcherDefault, MatcherFuzzy, MatcherExact, MatcherPrefix, MatcherInitials, MatcherSubstring, MatcherCaseSensitive:
+		return true
+	}
+
+	return false
+}
+
+// Or returns m, or fallback if m is not set.
+func (m Matcher) Or(fallback Matcher) Matcher {
+	if m == MatcherDefault {
+		return fallback
+	}
+
+	return m
+}
+
 func FuzzyScore(input, target string, exact bool) (int32, []int32, int32) {
-	runes := []rune(input)
-	chars := util.ToChars([]byte(target))
+	if exact {
+		return MatchScore(input, target, MatcherExact)
+	}
+
+	return MatchScore(input, target, MatcherFuzzy)
+}
+
+func MatchScore(input, target string, matcher Matcher) (int32, []int32, int32) {
+	p := newMatchPattern(input)
+	t := newMatchTarget(target)
+
+	return match(&p, &t, matcher, true, nil)
+}
+
+type matchPattern struct {
+	runes         []rune
+	lower         []rune
+	caseSensitive bool
+}
+
+func newMatchPattern(input string) matchPattern {
+	runes := []rune(foldString(input))
+
+	return matchPattern{
+		runes:         runes,
+		lower:         []rune(strings.ToLower(string(runes))),
+		caseSensitive: slices.ContainsFunc(runes, unicode.IsUpper),
+	}
+}
+
+type matchTarget struct {
+	chars   util.Chars
+	mapping []int
+}
+
+func newMatchTarget(target string) matchTarget {
+	folded, mapping := foldRunes(target)
+	if folded != nil {
+		return matchTarget{chars: util.RunesToChars(folded), mapping: mapping}
+	}
 
+	return matchTarget{chars: util.ToChars([]byte(target))}
+}
+
+// match scores the pattern against the target. The slab can be nil, it is used to avoid allocations when scoring in bulk.
+func match(p *matchPattern, t *matchTarget, matcher Matcher, withPos bool, slab *util.Slab) (int32, []int32, int32) {
 	var res algo.Result
 	var pos *[]int
 
-	if exact {
-		res, pos = algo.ExactMatchNaive(slices.ContainsFunc(runes, unicode.IsUpper), true, true, &chars, runes, true, nil)
-	} else {
-		res, pos = algo.FuzzyMatchV2(slices.ContainsFunc(runes, unicode.IsUpper), true, true, &chars, runes, true, nil)
+	switch matcher {
+	case MatcherExact:
+		res, pos = algo.ExactMatchNaive(p.caseSensitive, true, true, &t.chars, p.runes, withPos, slab)
+	case MatcherSubstring:
+		res, pos = algo.ExactMatchNaive(false, true, true, &t.chars, p.lower, withPos, slab)
+	case MatcherPrefix:
+		res, pos = algo.PrefixMatch(p.caseSensitive, true, true, &t.chars, p.runes, withPos, slab)
+	case MatcherInitials:
+		res, pos = initialsMatch(p.caseSensitive, &t.chars, p.runes)
+	case MatcherCaseSensitive:
+		res, pos = algo.FuzzyMatchV2(true, true, true, &t.chars, p.runes, withPos, slab)
+	default:
+		res, pos = algo.FuzzyMatchV2(p.caseSensitive, true, true, &t.chars, p.runes, withPos, slab)
 	}
 
 	var int32Slice []int32
@@ -34,11 +126,76 @@ func FuzzyScore(input, target string, exact bool) (int32, []int32, int32) {
 		for i, v := range intSlice {
 			int32Slice[i] = int32(v)
 		}
-	} else {
+	} else if withPos && res.Start >= 0 && res.End > res.Start {
+		// exact and prefix matches don't report positions, the match is contiguous though.
+		int32Slice = make([]int32, 0, res.End-res.Start)
+
+		for i := res.Start; i < res.End; i++ {
+			int32Slice = append(int32Slice, int32(i))
+		}
+	} else if withPos {
 		int32Slice = make([]int32, 0)
 	}
 
 	res.Score = res.Score - res.Start
 
+	if t.mapping != nil && res.Start >= 0 && res.Start < len(t.mapping) {
+		// highlight positions refer to the original runes, not the folded ones.
+		if withPos {
+			int32Slice = unfoldPositions(int32Slice, t.mapping)
+		}
+
+		res.Start = t.mapping[res.Start]
+	}
+
 	return int32(res.Score), int32Slice, int32(res.Start)
 }
+
+// initialsMatch matches the pattern against the first character of each word in text, f.e. "vsc" => "Visual Studio Code".
+func initialsMatch(caseSensitive bool, text *util.Chars, pattern []rune) (algo.Result, *[]int) {
+	if len(pattern) == 0 {
+		return algo.Result{Start: 0, End: 0, Score: 0}, nil
+	}
+
+	pos := make([]int, 0, len(pattern))
+	skipped := 0
+	prev := ' '
+
+	for i := 0; i < text.Length() && len(pos) < len(pattern); i++ {
+		char := text.Get(i)
+
+		if isWordStart(prev, char) {
+			if !caseSensitive {
+				char = unicode.ToLower(char)
+			}
+
+			if char == pattern[len(pos)] {
+				pos = append(pos, i)
+			} else if len(pos) > 0 {
+				skipped++
+			}
+		}
+
+		prev = text.Get(i)
+	}
+
+	if len(pos) < len(pattern) {
+		return algo.Result{Start: -1, End: -1, Score: 0}, nil
+	}
+
+	score := len(pattern)*(initialsScoreMatch+initialsBonus) + initialsBonus - skipped*initialsGapPenalty
+
+	return algo.Result{Start: pos[0], End: pos[len(pos)-1] + 1, Score: max(score, 1)}, &pos
+}
+
+func isWordStart(prev, char rune) bool {
+	if !unicode.IsLetter(char) && !unicode.IsDigit(char) {
+		return false
+	}
+
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(char) || unicode.IsLetter(prev) && unicode.IsDigit(char)
+}
@@ -0,0 +1,61 @@
+package common
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMatchers(t *testing.T) {
+	target := "Visual Studio Code"
+
+	tests := []struct {
+		matcher Matcher
+		input   string
+		want    []int32
+	}{
+		{MatcherFuzzy, "vsc", []int32{0, 7, 14}},
+		{MatcherFuzzy, "stud", []int32{7, 8, 9, 10}},
+		{MatcherExact, "code", []int32{14, 15, 16, 17}},
+		{MatcherExact, "STU", nil},
+		{MatcherExact, "vsc", nil},
+		{MatcherPrefix, "vis", []int32{0, 1, 2}},
+		{MatcherPrefix, "stud", nil},
+		{MatcherInitials, "vsc", []int32{0, 7, 14}},
+		{MatcherInitials, "VSC", []int32{0, 7, 14}},
+		{MatcherInitials, "vis", nil},
+		{MatcherSubstring, "STU", []int32{7, 8, 9}},
+		{MatcherSubstring, "vsc", nil},
+		{MatcherCaseSensitive, "VSC", []int32{0, 7, 14}},
+		{MatcherCaseSensitive, "vis", nil},
+	}
+
+	for _, tt := range tests {
+		score, pos, start := MatchScore(tt.input, target, tt.matcher)
+
+		if tt.want == nil {
+			if start >= 0 {
+				t.Errorf("%s: MatchScore(%q) = %d %v, want no match", tt.matcher, tt.input, score, pos)
+			}
+
+			continue
+		}
+
+		slices.Sort(pos)
+
+		if score <= 0 || start != tt.want[0] || !slices.Equal(pos, tt.want) {
+			t.Errorf("%s: MatchScore(%q) = %d %v %d, want positions %v", tt.matcher, tt.input, score, pos, start, tt.want)
+		}
+	}
+}
+
+func TestMatcherValid(t *testing.T) {
+	for _, m := range []Matcher{MatcherDefault, MatcherFuzzy, MatcherExact, MatcherPrefix, MatcherInitials, MatcherSubstring, MatcherCaseSensitive} {
+		if !m.Valid() {
+			t.Errorf("%q is not valid, want valid", m)
+		}
+	}
+
+	if Matcher("regex").Valid() {
+		t.Error("regex is valid, want invalid")
+	}
+}
@@ -0,0 +1,176 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common/wlr"
+)
+
+// Context describes the situation an item was activated in.
+type Context struct {
+	Hour      int
+	Weekday   int
+	App       string
+	Workspace string
+}
+
+// contextTTL is how long the focused workspace is cached before it is asked for again.
+const contextTTL = time.Second
+
+var (
+	ctxMut        sync.Mutex
+	ctxWorkspace  string
+	ctxUpdated    time.Time
+	ctxRefreshing bool
+)
+
+// current returns the context right now. The focused app is only known if a provider set up the window tracking.
+// Asking the compositor for the workspace is slow, so it is refreshed in the background and the cached one is used.
+func current() Context {
+	now := time.Now()
+
+	ctxMut.Lock()
+	ws := ctxWorkspace
+
+	if now.Sub(ctxUpdated) >= contextTTL && !ctxRefreshing {
+		ctxRefreshing = true
+		go refreshWorkspace()
+	}
+	ctxMut.Unlock()
+
+	c := Context{
+		Hour:      now.Hour(),
+		Weekday:   int(now.Weekday()),
+		Workspace: ws,
+	}
+
+	if wlr.IsRunning {
+		c.App = wlr.Focused()
+	}
+
+	return c
+}
+
+func refreshWorkspace() {
+	ws := workspace()
+
+	ctxMut.Lock()
+	ctxWorkspace = ws
+	ctxUpdated = time.Now()
+	ctxRefreshing = false
+	ctxMut.Unlock()
+}
+
+// keys returns the buckets a use in this context counts towards.
+func (c Context) keys() []string {
+	res := []string{fmt.Sprintf("h:%d", c.Hour), fmt.Sprintf("d:%d", c.Weekday)}
+
+	if c.App != "" {
+		res = append(res, "a:"+c.App)
+	}
+
+	if c.Workspace != "" {
+		res = append(res, "w:"+c.Workspace)
+	}
+
+	return res
+}
+
+// similarity returns how much the recorded uses of an item resemble the given context, from 0 to 1.
+// Neighbouring hours count half, app and workspace are only considered if they are known.
+func similarity(buckets map[string]*usage, c Context, now time.Time, halfLife time.Duration) float64 {
+	total, ok := buckets[""]
+	if !ok {
+		return 0
+	}
+
+	t := total.at(now, halfLife)
+	if t == 0 {
+		return 0
+	}
+
+	share := func(key string) float64 {
+		if u, ok := buckets[key]; ok {
+			return u.at(now, halfLife) / t
+		}
+
+		return 0
+	}
+
+	hour := share(fmt.Sprintf("h:%d", c.Hour)) + share(fmt.Sprintf("h:%d", (c.Hour+23)%24))/2 + share(fmt.Sprintf("h:%d", (c.Hour+1)%24))/2
+
+	sum := min(hour, 1) + share(fmt.Sprintf("d:%d", c.Weekday))
+	factors := 2.0
+
+	if c.App != "" {
+		sum += share("a:" + c.App)
+		factors++
+	}
+
+	if c.Workspace != "" {
+		sum += share("w:" + c.Workspace)
+		factors++
+	}
+
+	return sum / factors
+}
+
+// workspace asks the running compositor for the focused workspace.
+func workspace() string {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	switch os.Getenv("XDG_CURRENT_DESKTOP") {
+	case "Hyprland":
+		var ws struct {
+			ID int `json:"id"`
+		}
+
+		if run(ctx, &ws, "hyprctl", "-j", "activeworkspace") {
+			return fmt.Sprint(ws.ID)
+		}
+	case "niri":
+		var ws []struct {
+			Idx       int  `json:"idx"`
+			IsFocused bool `json:"is_focused"`
+		}
+
+		if run(ctx, &ws, "niri", "msg", "-j", "workspaces") {
+			for _, v := range ws {
+				if v.IsFocused {
+					return fmt.Sprint(v.Idx)
+				}
+			}
+		}
+	case "sway":
+		var ws []struct {
+			Name    string `json:"name"`
+			Focused bool   `json:"focused"`
+		}
+
+		if run(ctx, &ws, "swaymsg", "-t", "get_workspaces") {
+			for _, v := range ws {
+				if v.Focused {
+					return v.Name
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func run(ctx context.Context, v any, name string, args ...string) bool {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(out, v) == nil
+}
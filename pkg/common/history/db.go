@@ -0,0 +1,243 @@
+package history
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// schemaVersion is stored as user_version, databases with a lower one get upgraded when opened.
+const schemaVersion = 1
+
+var (
+	db     *sql.DB
+	dbOnce sync.Once
+)
+
+// openDB opens the history database shared by all providers. If it can't be opened, history is only kept in memory.
+func openDB() *sql.DB {
+	dbOnce.Do(func() {
+		path := common.CacheFile("history.db")
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			slog.Error("history", "createdirs", err)
+			return
+		}
+
+		d, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=5000")
+		if err != nil {
+			slog.Error("history", "open", err)
+			return
+		}
+
+		_, err = d.Exec(`CREATE TABLE IF NOT EXISTS events (
+			id INTEGER PRIMARY KEY,
+			provider TEXT NOT NULL,
+			query TEXT NOT NULL,
+			identifier TEXT NOT NULL,
+			used INTEGER NOT NULL,
+			app TEXT NOT NULL DEFAULT '',
+			workspace TEXT NOT NULL DEFAULT ''
+		)`)
+		if err != nil {
+			slog.Error("history", "open", err)
+			d.Close()
+			return
+		}
+
+		if err := upgrade(d); err != nil {
+			slog.Error("history", "upgrade", err)
+			d.Close()
+			return
+		}
+
+		_, err = d.Exec(`CREATE INDEX IF NOT EXISTS idx_events_provider ON events(provider, identifier)`)
+		if err != nil {
+			slog.Error("history", "open", err)
+			d.Close()
+			return
+		}
+
+		db = d
+
+		go prunePeriodically()
+	})
+
+	return db
+}
+
+// upgrade adds the columns missing in databases created by older versions.
+func upgrade(d *sql.DB) error {
+	var version int
+
+	if err := d.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+
+	if version >= schemaVersion {
+		return nil
+	}
+
+	rows, err := d.Query("SELECT name FROM pragma_table_info('events')")
+	if err != nil {
+		return err
+	}
+
+	columns := []string{}
+
+	for rows.Next() {
+		var name string
+
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+
+		columns = append(columns, name)
+	}
+
+	rows.Close()
+
+	for _, v := range []string{"app", "workspace"} {
+		if slices.Contains(columns, v) {
+			continue
+		}
+
+		if _, err := d.Exec(fmt.Sprintf("ALTER TABLE events ADD COLUMN %s TEXT NOT NULL DEFAULT ''", v)); err != nil {
+			return err
+		}
+	}
+
+	_, err = d.Exec(fmt.Sprintf("PRAGMA user_version = %d", schemaVersion))
+
+	return err
+}
+
+func insertEvent(provider, query, identifier string, used time.Time, c Context) {
+	if openDB() == nil {
+		return
+	}
+
+	_, err := db.Exec("INSERT INTO events (provider, query, identifier, used, app, workspace) VALUES (?, ?, ?, ?, ?, ?)", provider, query, identifier, used.Unix(), c.App, c.Workspace)
+	if err != nil {
+		slog.Error("history", "insert", err)
+	}
+}
+
+func deleteEvents(provider, identifier string) {
+	if openDB() == nil {
+		return
+	}
+
+	_, err := db.Exec("DELETE FROM events WHERE provider = ? AND identifier = ?", provider, identifier)
+	if err != nil {
+		slog.Error("history", "delete", err)
+	}
+}
+
+// readEvents calls fn for every recorded usage of a provider, oldest first.
+func readEvents(provider string, fn func(query, identifier string, used time.Time, c Context)) error {
+	if openDB() == nil {
+		return nil
+	}
+
+	rows, err := db.Query("SELECT query, identifier, used, app, workspace FROM events WHERE provider = ? ORDER BY used", provider)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var query, identifier, app, workspace string
+		var used int64
+
+		if err := rows.Scan(&query, &identifier, &used, &app, &workspace); err != nil {
+			return err
+		}
+
+		t := time.Unix(used, 0)
+
+		fn(query, identifier, t, Context{Hour: t.Hour(), Weekday: int(t.Weekday()), App: app, Workspace: workspace})
+	}
+
+	return rows.Err()
+}
+
+// legacyData is an entry of the gob file used before the history database.
+type legacyData struct {
+	LastUsed time.Time
+	Amount   int
+}
+
+type legacyHistory struct {
+	Provider string
+	Data     map[string]map[string]*legacyData
+}
+
+// migrate moves the old gob history of a provider into the database. Every use is recorded at the last time it was used.
+func migrate(provider string) {
+	file := common.CacheFile(fmt.Sprintf("%s_history.gob", provider))
+
+	if !common.FileExists(file) || openDB() == nil {
+		return
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		slog.Error("history", "migrate", err)
+		return
+	}
+
+	var h legacyHistory
+
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&h); err != nil {
+		slog.Error("history", "migrate", err)
+		return
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		slog.Error("history", "migrate", err)
+		return
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare("INSERT INTO events (provider, query, identifier, used) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		slog.Error("history", "migrate", err)
+		return
+	}
+	defer stmt.Close()
+
+	for query, items := range h.Data {
+		for identifier, v := range items {
+			for range v.Amount {
+				if _, err := stmt.Exec(provider, query, identifier, v.LastUsed.Unix()); err != nil {
+					slog.Error("history", "migrate", err)
+					return
+				}
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		slog.Error("history", "migrate", err)
+		return
+	}
+
+	if err := os.Remove(file); err != nil {
+		slog.Error("history", "migrate", err)
+	}
+
+	slog.Info("history", "migrated", provider)
+}
@@ -1,13 +1,14 @@
 // Package history provides functions to save and load history in a streamlined way.
+//
+// Every activation is recorded as an event in a single SQLite database shared by all providers.
+// Items are ranked by frecency: each use adds a weight of 1, which halves every history_half_life days.
+// The context of each use is recorded as well, items usually activated in a context similar to the current
+// one get boosted by history_context_weight.
 package history
 
 import (
-	"bytes"
-	"encoding/gob"
-	"fmt"
 	"log/slog"
-	"os"
-	"path/filepath"
+	"math"
 	"strings"
 	"sync"
 	"time"
@@ -15,171 +16,229 @@ import (
 	"github.com/abenz1267/elephant/v2/pkg/common"
 )
 
-type HistoryData struct {
-	LastUsed time.Time
-	Amount   int
+const ActionDelete = "erase_history"
+
+// defaults used if the elephant config isn't loaded yet.
+const (
+	defaultHalfLife      = 7 * 24 * time.Hour
+	defaultContextWeight = 1
+)
+
+// usage is the decayed weight of an item at the time it was last updated.
+type usage struct {
+	Weight  float64
+	Updated time.Time
 }
 
-const ActionDelete = "erase_history"
+// at returns the weight decayed up to t.
+func (u *usage) at(t time.Time, halfLife time.Duration) float64 {
+	return u.Weight * math.Exp2(-float64(t.Sub(u.Updated))/float64(halfLife))
+}
 
-// TODO: this is global for every history ... should not be the case. Just a crutch because of gob encoding.
-var mut sync.Mutex
+// add decays the weight up to t and adds a single use.
+func (u *usage) add(t time.Time, halfLife time.Duration) {
+	u.Weight = u.at(t, halfLife) + 1
+	u.Updated = t
+}
 
 type History struct {
 	Provider string
-	Data     map[string]map[string]*HistoryData
-}
 
-func (h *History) Remove(identifier string) {
-	mut.Lock()
-	defer mut.Unlock()
+	mut  sync.RWMutex
+	once sync.Once
+	data map[string]map[string]*usage
 
-	for _, v := range h.Data {
-		delete(v, identifier)
+	// contexts holds the decayed weight of every context bucket per identifier, "" being the total.
+	contexts map[string]map[string]*usage
+}
+
+func Load(provider string) *History {
+	h := &History{
+		Provider: provider,
+		data:     make(map[string]map[string]*usage),
+		contexts: make(map[string]map[string]*usage),
 	}
 
-	h.writeFile()
+	loadedMut.Lock()
+	loaded[provider] = h
+	loadedMut.Unlock()
+
+	return h
 }
 
-func (h *History) Save(query, identifier string) {
-	mut.Lock()
-	defer mut.Unlock()
-
-	if _, ok := h.Data[query]; ok {
-		if val, ok := h.Data[query][identifier]; ok {
-			h.Data[query][identifier].LastUsed = time.Now()
-			h.Data[query][identifier].Amount = min(val.Amount+1, 10)
-		} else {
-			h.Data[query][identifier] = &HistoryData{
-				LastUsed: time.Now(),
-				Amount:   1,
+// load reads the recorded events on first use, as providers call Load before the config is loaded.
+func (h *History) load() {
+	h.once.Do(func() {
+		migrate(h.Provider)
+
+		if openDB() != nil {
+			if _, err := prune(h.Provider); err != nil {
+				slog.Error("history", "prune", err)
 			}
 		}
-	} else {
-		h.Data[query] = make(map[string]*HistoryData)
-		h.Data[query][identifier] = &HistoryData{
-			LastUsed: time.Now(),
-			Amount:   1,
-		}
-	}
 
-	h.writeFile()
+		h.mut.Lock()
+		h.read()
+		h.mut.Unlock()
+	})
 }
 
-func (h *History) writeFile() {
-	var b bytes.Buffer
-	encoder := gob.NewEncoder(&b)
+// read adds all recorded events, the caller has to hold the lock.
+func (h *History) read() {
+	hl := halfLife()
 
-	err := encoder.Encode(h)
+	err := readEvents(h.Provider, func(query, identifier string, used time.Time, c Context) {
+		h.add(query, identifier, used, c, hl)
+	})
 	if err != nil {
-		slog.Error("history", "encode", err)
-		return
+		slog.Error("history", "load", err)
 	}
+}
 
-	err = os.MkdirAll(filepath.Dir(common.CacheFile(fmt.Sprintf("%s_history.gob", h.Provider))), 0o755)
-	if err != nil {
-		slog.Error("history", "createdirs", err)
-		return
+func (h *History) add(query, identifier string, t time.Time, c Context, halfLife time.Duration) {
+	addTo(h.data, query, identifier, t, halfLife)
+
+	for _, k := range append(c.keys(), "") {
+		addTo(h.contexts, identifier, k, t, halfLife)
 	}
+}
 
-	err = os.WriteFile(common.CacheFile(fmt.Sprintf("%s_history.gob", h.Provider)), b.Bytes(), 0o600)
-	if err != nil {
-		slog.Error("history", "writefile", err)
+func addTo(m map[string]map[string]*usage, outer, inner string, t time.Time, halfLife time.Duration) {
+	if _, ok := m[outer]; !ok {
+		m[outer] = make(map[string]*usage)
+	}
+
+	if _, ok := m[outer][inner]; !ok {
+		m[outer][inner] = &usage{}
 	}
+
+	m[outer][inner].add(t, halfLife)
 }
 
-func (h *History) FindUsage(query, identifier string) (int, time.Time, int) {
-	mut.Lock()
-	defer mut.Unlock()
+func (h *History) Remove(identifier string) {
+	h.load()
 
-	var usage int
-	var lastUsed time.Time
+	h.mut.Lock()
+	for _, v := range h.data {
+		delete(v, identifier)
+	}
 
-	if query == "" {
-		for _, v := range h.Data {
-			if n, ok := v[identifier]; ok {
-				usage += n.Amount
-				if n.LastUsed.After(lastUsed) {
-					lastUsed = n.LastUsed
-				}
-			}
-		}
-		return usage, lastUsed, 0
+	delete(h.contexts, identifier)
+	h.mut.Unlock()
+
+	deleteEvents(h.Provider, identifier)
+}
+
+// Save records a use, unless incognito mode is enabled or the identifier matches the provider's history_exclude.
+func (h *History) Save(query, identifier string) {
+	if !common.ShouldRecord(h.Provider, identifier) {
+		return
 	}
 
-	delta := 0
+	h.load()
 
-	for k, v := range h.Data {
-		if strings.HasPrefix(query, k) || strings.HasPrefix(k, query) {
-			delta = len(k) - len(query)
+	now := time.Now()
+	c := current()
 
-			if delta < 0 {
-				delta = delta * -1
-			}
+	h.mut.Lock()
+	h.add(query, identifier, now, c, halfLife())
+	h.mut.Unlock()
 
-			if n, ok := v[identifier]; ok {
-				usage += n.Amount
-				if n.LastUsed.After(lastUsed) {
-					lastUsed = n.LastUsed
-				}
-			}
+	insertEvent(h.Provider, query, identifier, now, c)
+}
+
+// FindUsage returns the frecency of an item for queries sharing a prefix with the given one,
+// and the smallest length difference between such a query and the given one.
+func (h *History) FindUsage(query, identifier string) (float64, int) {
+	h.load()
+
+	h.mut.RLock()
+	defer h.mut.RUnlock()
+
+	now := time.Now()
+	hl := halfLife()
+
+	var frecency float64
+
+	delta := -1
+
+	for k, v := range h.data {
+		if query != "" && !strings.HasPrefix(query, k) && !strings.HasPrefix(k, query) {
+			continue
+		}
+
+		n, ok := v[identifier]
+		if !ok {
+			continue
+		}
+
+		frecency += n.at(now, hl)
+
+		if query == "" {
+			continue
+		}
+
+		d := len(k) - len(query)
+
+		if d < 0 {
+			d = d * -1
+		}
+
+		if delta == -1 || d < delta {
+			delta = d
 		}
 	}
 
-	return usage, lastUsed, delta
+	return frecency, max(delta, 0)
 }
 
+// maxUsageScore caps the usage score, so history only breaks ties between good matches instead of outranking them.
+const maxUsageScore = 100
+
 func (h *History) CalcUsageScore(query, identifier string) int32 {
-	amount, last, delta := h.FindUsage(query, identifier)
+	frecency, delta := h.FindUsage(query, identifier)
 
-	if amount == 0 {
+	if frecency == 0 {
 		return 0
 	}
 
-	base := 10
-
-	if amount > 0 {
-		today := time.Now()
-		duration := today.Sub(last)
-		days := int(duration.Hours() / 24)
+	if w := contextWeight(); w > 0 {
+		frecency *= 1 + w*h.similarity(identifier, current())
+	}
 
-		if days > 0 {
-			base -= days
-		}
+	res := min(max(int(frecency*10), 1), maxUsageScore)
 
-		res := max(base*amount, 1)
+	if delta != 0 {
+		return int32(res / delta)
+	}
 
-		if delta != 0 {
-			return int32(res / delta)
-		}
+	return int32(res)
+}
 
-		return int32(res)
-	}
+// similarity returns how much the recorded uses of an item resemble the given context, from 0 to 1.
+func (h *History) similarity(identifier string, c Context) float64 {
+	h.mut.RLock()
+	defer h.mut.RUnlock()
 
-	return 0
+	return similarity(h.contexts[identifier], c, time.Now(), halfLife())
 }
 
-func Load(provider string) *History {
-	h := History{
-		Data:     make(map[string]map[string]*HistoryData),
-		Provider: provider,
+func contextWeight() float64 {
+	cfg := common.GetElephantConfig()
+
+	if cfg == nil {
+		return defaultContextWeight
 	}
 
-	file := common.CacheFile(fmt.Sprintf("%s_history.gob", provider))
+	return cfg.HistoryContextWeight
+}
 
-	if common.FileExists(file) {
-		f, err := os.ReadFile(file)
-		if err != nil {
-			slog.Error("history", "load", err)
-		} else {
-			decoder := gob.NewDecoder(bytes.NewReader(f))
+func halfLife() time.Duration {
+	cfg := common.GetElephantConfig()
 
-			err = decoder.Decode(&h)
-			if err != nil {
-				slog.Error("history", "decoding", err)
-			}
-		}
+	if cfg == nil || cfg.HistoryHalfLife <= 0 {
+		return defaultHalfLife
 	}
 
-	return &h
+	return time.Duration(cfg.HistoryHalfLife * float64(24*time.Hour))
 }
@@ -0,0 +1,87 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsageDecay(t *testing.T) {
+	hl := 24 * time.Hour
+	start := time.Now()
+
+	u := usage{}
+	u.add(start, hl)
+	u.add(start, hl)
+
+	if got := u.at(start.Add(hl), hl); got != 1 {
+		t.Fatalf("got %f after one half-life, want 1", got)
+	}
+
+	u.add(start.Add(2*hl), hl)
+
+	if u.Weight != 1.5 {
+		t.Fatalf("got %f after adding a use, want 1.5", u.Weight)
+	}
+}
+
+func TestFindUsageDelta(t *testing.T) {
+	h := Load("test")
+	h.once.Do(func() {})
+
+	now := time.Now()
+	h.add("fi", "firefox", now, Context{}, defaultHalfLife)
+	h.add("fire", "firefox", now, Context{}, defaultHalfLife)
+	h.add("thu", "thunderbird", now, Context{}, defaultHalfLife)
+
+	frecency, delta := h.FindUsage("fir", "firefox")
+
+	if frecency < 1.99 || frecency > 2 || delta != 1 {
+		t.Fatalf("got %f %d, want 2 1", frecency, delta)
+	}
+
+	if frecency, _ := h.FindUsage("fir", "thunderbird"); frecency != 0 {
+		t.Fatalf("got %f for a different query, want 0", frecency)
+	}
+}
+
+func TestContextSimilarity(t *testing.T) {
+	h := Load("test")
+	h.once.Do(func() {})
+
+	now := time.Now()
+	morning := Context{Hour: 8, Weekday: 1, Workspace: "1"}
+	evening := Context{Hour: 20, Weekday: 1, Workspace: "2"}
+
+	h.add("mail", "thunderbird", now, morning, defaultHalfLife)
+	h.add("mail", "thunderbird", now, morning, defaultHalfLife)
+	h.add("mail", "aerc", now, evening, defaultHalfLife)
+
+	if a, b := h.similarity("thunderbird", morning), h.similarity("aerc", morning); a <= b {
+		t.Fatalf("got %f for thunderbird and %f for aerc in the morning, want thunderbird higher", a, b)
+	}
+
+	if a, b := h.similarity("thunderbird", evening), h.similarity("aerc", evening); a >= b {
+		t.Fatalf("got %f for thunderbird and %f for aerc in the evening, want aerc higher", a, b)
+	}
+}
+
+func TestUsageScoreCap(t *testing.T) {
+	h := Load("test")
+	h.once.Do(func() {})
+
+	now := time.Now()
+
+	for range 50 {
+		h.add("fire", "firefox", now, Context{}, defaultHalfLife)
+	}
+
+	h.add("thu", "thunderbird", now, Context{}, defaultHalfLife)
+
+	if got := h.CalcUsageScore("fire", "firefox"); got != maxUsageScore {
+		t.Fatalf("got %d for a frequent item, want the cap %d", got, maxUsageScore)
+	}
+
+	if got := h.CalcUsageScore("thu", "thunderbird"); got < 10 || got >= maxUsageScore {
+		t.Fatalf("got %d for a single use, want it below the cap", got)
+	}
+}
@@ -0,0 +1,300 @@
+package history
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/abenz1267/elephant/v2/pkg/common"
+)
+
+var ErrNoDatabase = errors.New("history database not available")
+
+// Item summarizes the recorded uses of an item.
+type Item struct {
+	Provider   string
+	Identifier string
+	Uses       int
+	LastUsed   time.Time
+	Frecency   float64
+}
+
+// Event is a single recorded use, as stored in the database.
+type Event struct {
+	Provider   string
+	Query      string
+	Identifier string
+	Used       time.Time
+	App        string
+	Workspace  string
+}
+
+var (
+	loadedMut sync.Mutex
+	loaded    = make(map[string]*History)
+)
+
+// List returns every item with recorded uses, most recently used first. An empty provider lists all of them.
+func List(provider string) ([]Item, error) {
+	if openDB() == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := db.Query(`SELECT provider, identifier, COUNT(*), MAX(used) FROM events
+		WHERE ? = '' OR provider = ? GROUP BY provider, identifier ORDER BY MAX(used) DESC`, provider, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []Item{}
+
+	for rows.Next() {
+		var i Item
+		var used int64
+
+		if err := rows.Scan(&i.Provider, &i.Identifier, &i.Uses, &used); err != nil {
+			return nil, err
+		}
+
+		i.LastUsed = time.Unix(used, 0)
+
+		if h := get(i.Provider); h != nil {
+			i.Frecency, _ = h.FindUsage("", i.Identifier)
+		}
+
+		res = append(res, i)
+	}
+
+	return res, rows.Err()
+}
+
+// Export returns all recorded uses, oldest first. An empty provider exports all of them.
+func Export(provider string) ([]Event, error) {
+	if openDB() == nil {
+		return nil, ErrNoDatabase
+	}
+
+	rows, err := db.Query(`SELECT provider, query, identifier, used, app, workspace FROM events
+		WHERE ? = '' OR provider = ? ORDER BY used`, provider, provider)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []Event{}
+
+	for rows.Next() {
+		var e Event
+		var used int64
+
+		if err := rows.Scan(&e.Provider, &e.Query, &e.Identifier, &used, &e.App, &e.Workspace); err != nil {
+			return nil, err
+		}
+
+		e.Used = time.Unix(used, 0)
+
+		res = append(res, e)
+	}
+
+	return res, rows.Err()
+}
+
+// Import adds previously exported uses and returns how many were added. Uses already recorded with the same
+// provider, query, identifier and time are skipped, so importing the same export twice doesn't count them twice.
+func Import(events []Event) (int, error) {
+	if openDB() == nil {
+		return 0, ErrNoDatabase
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO events (provider, query, identifier, used, app, workspace) SELECT ?, ?, ?, ?, ?, ?
+		WHERE NOT EXISTS (SELECT 1 FROM events WHERE provider = ? AND query = ? AND identifier = ? AND used = ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	providers := make(map[string]struct{})
+	count := 0
+
+	for _, e := range events {
+		res, err := stmt.Exec(e.Provider, e.Query, e.Identifier, e.Used.Unix(), e.App, e.Workspace, e.Provider, e.Query, e.Identifier, e.Used.Unix())
+		if err != nil {
+			return 0, err
+		}
+
+		if n, _ := res.RowsAffected(); n > 0 {
+			providers[e.Provider] = struct{}{}
+			count += int(n)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	for k := range providers {
+		reload(k)
+	}
+
+	return count, nil
+}
+
+// Clear deletes all recorded uses and returns how many were deleted. An empty provider clears all of them.
+func Clear(provider string) (int, error) {
+	if openDB() == nil {
+		return 0, ErrNoDatabase
+	}
+
+	res, err := db.Exec("DELETE FROM events WHERE ? = '' OR provider = ?", provider, provider)
+	if err != nil {
+		return 0, err
+	}
+
+	reload(provider)
+
+	n, err := res.RowsAffected()
+
+	return int(n), err
+}
+
+// Prune applies history_max_age and history_max_entries and returns how many uses were deleted.
+// An empty provider prunes all of them.
+func Prune(provider string) (int, error) {
+	if openDB() == nil {
+		return 0, ErrNoDatabase
+	}
+
+	providers := []string{provider}
+
+	if provider == "" {
+		var err error
+
+		providers, err = recordedProviders()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	count := 0
+
+	for _, v := range providers {
+		n, err := prune(v)
+		if err != nil {
+			return count, err
+		}
+
+		if n > 0 {
+			reload(v)
+		}
+
+		count += n
+	}
+
+	return count, nil
+}
+
+func prune(provider string) (int, error) {
+	cfg := common.GetElephantConfig()
+	if cfg == nil {
+		return 0, nil
+	}
+
+	count := 0
+
+	if cfg.HistoryMaxAge > 0 {
+		before := time.Now().Add(-time.Duration(cfg.HistoryMaxAge) * 24 * time.Hour)
+
+		res, err := db.Exec("DELETE FROM events WHERE provider = ? AND used < ?", provider, before.Unix())
+		if err != nil {
+			return count, err
+		}
+
+		n, _ := res.RowsAffected()
+		count += int(n)
+	}
+
+	if cfg.HistoryMaxEntries > 0 {
+		res, err := db.Exec(`DELETE FROM events WHERE provider = ? AND identifier NOT IN (
+			SELECT identifier FROM events WHERE provider = ? GROUP BY identifier ORDER BY MAX(used) DESC LIMIT ?
+		)`, provider, provider, cfg.HistoryMaxEntries)
+		if err != nil {
+			return count, err
+		}
+
+		n, _ := res.RowsAffected()
+		count += int(n)
+	}
+
+	return count, nil
+}
+
+func recordedProviders() ([]string, error) {
+	rows, err := db.Query("SELECT DISTINCT provider FROM events")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []string{}
+
+	for rows.Next() {
+		var p string
+
+		if err := rows.Scan(&p); err != nil {
+			return nil, err
+		}
+
+		res = append(res, p)
+	}
+
+	return res, rows.Err()
+}
+
+func get(provider string) *History {
+	loadedMut.Lock()
+	defer loadedMut.Unlock()
+
+	return loaded[provider]
+}
+
+// reload re-reads the recorded uses of loaded histories after the database changed. An empty provider reloads all of them.
+func reload(provider string) {
+	loadedMut.Lock()
+	histories := []*History{}
+
+	for k, v := range loaded {
+		if provider == "" || k == provider {
+			histories = append(histories, v)
+		}
+	}
+	loadedMut.Unlock()
+
+	for _, h := range histories {
+		h.load()
+
+		h.mut.Lock()
+		h.data = make(map[string]map[string]*usage)
+		h.contexts = make(map[string]map[string]*usage)
+		h.read()
+		h.mut.Unlock()
+	}
+}
+
+// prunePeriodically is started once, so retention also applies to a service running for a long time.
+func prunePeriodically() {
+	for {
+		time.Sleep(24 * time.Hour)
+
+		if _, err := Prune(""); err != nil {
+			slog.Error("history", "prune", err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestImportSkipsDuplicates(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if openDB() == nil {
+		t.Skip("database not available")
+	}
+
+	used := time.Unix(time.Now().Unix(), 0)
+
+	events := []Event{
+		{Provider: "import", Query: "fire", Identifier: "firefox", Used: used},
+		{Provider: "import", Query: "fire", Identifier: "firefox", Used: used.Add(time.Minute)},
+	}
+
+	if n, err := Import(events); err != nil || n != 2 {
+		t.Fatalf("got %d %v, want 2 added", n, err)
+	}
+
+	if n, err := Import(events); err != nil || n != 0 {
+		t.Fatalf("got %d %v importing again, want 0 added", n, err)
+	}
+
+	res, err := Export("import")
+	if err != nil || len(res) != 2 {
+		t.Fatalf("got %d %v, want 2 events", len(res), err)
+	}
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"log/slog"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// incognito suspends recording history, clipboard contents and calculations for all providers.
+var incognito atomic.Bool
+
+func Incognito() bool {
+	return incognito.Load()
+}
+
+func SetIncognito(val bool) {
+	incognito.Store(val)
+
+	slog.Info("elephant", "incognito", val)
+}
+
+var (
+	excludeMut   sync.Mutex
+	excludeCache = make(map[string]*regexp.Regexp)
+)
+
+// ShouldRecord reports whether a value, f.e. an identifier or clipboard content, may be recorded by the provider.
+// It's false in incognito mode or if the value matches one of the provider's history_exclude patterns.
+// The patterns are taken from the provider's loaded config, changes apply once it's reloaded.
+func ShouldRecord(provider, value string) bool {
+	if Incognito() {
+		return false
+	}
+
+	for _, v := range ProviderSettings(provider).HistoryExclude {
+		excludeMut.Lock()
+		re, ok := excludeCache[v]
+
+		if !ok {
+			var err error
+
+			re, err = regexp.Compile(v)
+			if err != nil {
+				slog.Error(provider, "history_exclude", err)
+			}
+
+			excludeCache[v] = re
+		}
+		excludeMut.Unlock()
+
+		if re != nil && re.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -4,15 +4,20 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/adrg/xdg"
 	"github.com/charlievieth/fastwalk"
+	"github.com/fsnotify/fsnotify"
 	"github.com/pelletier/go-toml/v2"
 
 	lua "github.com/yuin/gopher-lua"
@@ -26,46 +31,65 @@ var (
 type MenuConfig struct {
 	Config `koanf:",squash"`
 	Paths  []string `koanf:"paths" desc:"additional paths to check for menu definitions." default:""`
+
+	LuaTimeout       int  `koanf:"lua_timeout" desc:"milliseconds a call into a Lua menu may take before it's aborted, 0 disables it" default:"3000"`
+	LuaRestrictLibs  bool `koanf:"lua_restrict_libs" desc:"only open the package, io, os, debug and channel libraries and the run, readFile, setClipboard and notify functions for Lua menus listing them in Libraries. off by default, so every menu can use them" default:"false"`
+	LuaCallStackSize int  `koanf:"lua_call_stack_size" desc:"max depth of nested Lua calls, limits runaway recursion" default:"256"`
+	LuaRegistrySize  int  `koanf:"lua_registry_size" desc:"max slots of the Lua data stack, this doesn't limit memory used by tables or strings" default:"5120"`
 }
 
 type Menu struct {
-	HideFromProviderlist bool              `toml:"hide_from_providerlist" desc:"hides a provider from the providerlist provider. provider provider." default:"false"`
-	Name                 string            `toml:"name" desc:"name of the menu"`
-	NamePretty           string            `toml:"name_pretty" desc:"prettier name you usually want to display to the user."`
-	Description          string            `toml:"description" desc:"used as a subtext"`
-	Icon                 string            `toml:"icon" desc:"default icon"`
-	Action               string            `toml:"action" desc:"default menu action to use"`
-	Actions              map[string]string `toml:"actions" desc:"global actions"`
-	AsyncActions         []string          `toml:"async_actions" desc:"set which actions should update the item on the client asynchronously"`
-	SearchName           bool              `toml:"search_name" desc:"wether to search for the menu name as well when searching globally" default:"false"`
-	Cache                bool              `toml:"cache" desc:"will cache the results of the lua script on startup"`
-	Entries              []Entry           `toml:"entries" desc:"menu items"`
-	Terminal             bool              `toml:"terminal" desc:"execute action in terminal or not"`
-	Keywords             []string          `toml:"keywords" desc:"searchable keywords"`
-	FixedOrder           bool              `toml:"fixed_order" desc:"don't sort entries alphabetically"`
-	History              bool              `toml:"history" desc:"make use of history for sorting"`
-	HistoryWhenEmpty     bool              `toml:"history_when_empty" desc:"consider history when query is empty"`
-	MinScore             int32             `toml:"min_score" desc:"minimum score for items to be displayed" default:"depends on provider"`
-	Parent               string            `toml:"parent" desc:"defines the parent menu" default:""`
-	SubMenu              string            `toml:"submenu" desc:"defines submenu to trigger on activation" default:""`
+	HideFromProviderlist bool               `toml:"hide_from_providerlist" desc:"hides a provider from the providerlist provider. provider provider." default:"false"`
+	Name                 string             `toml:"name" desc:"name of the menu"`
+	NamePretty           string             `toml:"name_pretty" desc:"prettier name you usually want to display to the user."`
+	Description          string             `toml:"description" desc:"used as a subtext"`
+	Icon                 string             `toml:"icon" desc:"default icon"`
+	Action               string             `toml:"action" desc:"default menu action to use"`
+	Actions              map[string]string  `toml:"actions" desc:"global actions"`
+	AsyncActions         []string           `toml:"async_actions" desc:"set which actions should update the item on the client asynchronously"`
+	SearchName           bool               `toml:"search_name" desc:"wether to search for the menu name as well when searching globally" default:"false"`
+	Cache                bool               `toml:"cache" desc:"will cache the results of the lua script or generator on startup"`
+	Entries              []Entry            `toml:"entries" desc:"menu items"`
+	Terminal             bool               `toml:"terminal" desc:"execute action in terminal or not"`
+	Keywords             []string           `toml:"keywords" desc:"searchable keywords"`
+	FixedOrder           bool               `toml:"fixed_order" desc:"don't sort entries alphabetically"`
+	History              bool               `toml:"history" desc:"make use of history for sorting"`
+	HistoryWhenEmpty     bool               `toml:"history_when_empty" desc:"consider history when query is empty"`
+	MinScore             int32              `toml:"min_score" desc:"minimum score for items to be displayed" default:"depends on provider"`
+	Parent               string             `toml:"parent" desc:"defines the parent menu" default:""`
+	SubMenu              string             `toml:"submenu" desc:"defines submenu to trigger on activation" default:""`
+	Async                bool               `toml:"async" desc:"create entries in the background and serve the last result, lua and generated menus only" default:"false"`
+	RefreshInterval      int                `toml:"refresh_interval" desc:"seconds between re-creating the entries in the background, 0 disables it, lua and generated menus only" default:"0"`
+	Timeout              int                `toml:"timeout" desc:"milliseconds the generator or a lua call may take, 0 uses lua_timeout" default:"0"`
+	Generator            string             `toml:"generator" desc:"command whose output creates the entries" default:""`
+	Format               string             `toml:"format" desc:"output format of the generator: lines, json, tsv" default:"lines"`
+	Mapping              Mapping            `toml:"mapping" desc:"fields of the generator output used for entries"`
+	Inputs               map[string][]Input `toml:"inputs" desc:"input fields per action, asked for by the frontend before activation"`
+	When                 When               `toml:"when" desc:"conditions for showing the menu"`
 
 	// internal
 	LuaString string
-	IsLua     bool `toml:"-"`
+	LuaPath   string `toml:"-"`
+	IsLua     bool   `toml:"-"`
+
+	lua     *luaMenu
+	dynamic *dynamicMenu
 }
 
-func (m *Menu) NewLuaState() *lua.LState {
-	l := lua.NewState()
+// luaMenu is the long-lived state of a Lua menu. It is kept between calls, so scripts can cache data.
+// A Lua state isn't safe for concurrent use, so every call holds mu.
+type luaMenu struct {
+	mu          sync.Mutex
+	l           *lua.LState
+	initialized bool
+}
 
-	if err := l.DoString(m.LuaString); err != nil {
-		slog.Error(m.Name, "newLuaState", err)
-		l.Close()
-		return nil
-	}
+// newLuaState runs the script with all host functions set.
+func (m *Menu) newLuaState() (*lua.LState, error) {
+	l := newSandboxedState()
 
-	if l == nil {
-		slog.Error(m.Name, "newLuaState", "lua state is nil")
-		return nil
+	if !MenuConfigLoaded.LuaRestrictLibs {
+		openLuaLibs(l, slices.Concat(slices.Collect(maps.Keys(optionalLuaLibs)), slices.Collect(maps.Keys(optionalLuaFunctions))))
 	}
 
 	l.SetGlobal("lastMenuValue", l.NewFunction(GetLastMenuValue))
@@ -74,7 +98,71 @@ func (m *Menu) NewLuaState() *lua.LState {
 	l.SetGlobal("jsonEncode", l.NewFunction(JSONEncode))
 	l.SetGlobal("jsonDecode", l.NewFunction(JSONDecode))
 
-	return l
+	m.setLuaHostFunctions(l)
+
+	if err := m.callLua(l, func() error { return l.DoString(m.LuaString) }); err != nil {
+		if !errors.Is(err, ErrLuaTimeout) {
+			l.Close()
+		}
+
+		return nil, err
+	}
+
+	if MenuConfigLoaded.LuaRestrictLibs {
+		openLuaLibs(l, requestedLuaLibs(l))
+	}
+
+	return l, nil
+}
+
+// WithLuaState calls fn with the menu's Lua state, calling the script's Init function first if this is the first use.
+// Both run with the menu's timeout. If it's exceeded, the state is dropped and a new one is created on the next call.
+func (m *Menu) WithLuaState(fn func(l *lua.LState) error) error {
+	if m.lua == nil {
+		return ErrNoLuaState
+	}
+
+	m.lua.mu.Lock()
+	defer m.lua.mu.Unlock()
+
+	if m.lua.l == nil {
+		l, err := m.newLuaState()
+		if err != nil {
+			m.setErr(err)
+			return err
+		}
+
+		m.lua.l = l
+		m.lua.initialized = false
+	}
+
+	l := m.lua.l
+
+	err := m.callLua(l, func() error {
+		if !m.lua.initialized {
+			m.lua.initialized = true
+
+			if init := l.GetGlobal("Init"); init.Type() == lua.LTFunction {
+				if err := l.CallByParam(lua.P{
+					Fn:      init,
+					NRet:    0,
+					Protect: true,
+				}); err != nil {
+					slog.Error(m.Name, "Init", err)
+				}
+			}
+		}
+
+		return fn(l)
+	})
+
+	if errors.Is(err, ErrLuaTimeout) {
+		m.lua.l = nil
+	}
+
+	m.setErr(err)
+
+	return err
 }
 
 var (
@@ -222,21 +310,36 @@ func goValueToLua(L *lua.LState, val any) lua.LValue {
 	}
 }
 
-func (m *Menu) CreateLuaEntries() {
-	state := m.NewLuaState()
+// CreateLuaEntries replaces the entries with the ones returned by the script's GetEntries. On error, the entries are kept.
+// Entries arriving after the timeout are dropped.
+func (m *Menu) CreateLuaEntries() error {
+	var entries []Entry
 
-	if state == nil {
-		slog.Error(m.Name, "CreateLuaEntries", "no lua state")
-		return
+	err := m.WithLuaState(func(state *lua.LState) error {
+		res, err := m.luaEntries(state)
+		if err == nil {
+			entries = res
+		}
+
+		return err
+	})
+	if err != nil {
+		slog.Error(m.Name, "CreateLuaEntries", err)
+		return err
 	}
 
+	m.setEntries(entries)
+
+	return nil
+}
+
+func (m *Menu) luaEntries(state *lua.LState) ([]Entry, error) {
 	if err := state.CallByParam(lua.P{
 		Fn:      state.GetGlobal("GetEntries"),
 		NRet:    1,
 		Protect: true,
 	}); err != nil {
-		slog.Error(m.Name, "GetLuaEntries", err)
-		return
+		return nil, err
 	}
 
 	res := []Entry{}
@@ -250,31 +353,31 @@ func (m *Menu) CreateLuaEntries() {
 				entry := Entry{}
 
 				if text := item.RawGetString("Text"); text != lua.LNil {
-					entry.Text = string(text.(lua.LString))
+					entry.Text = lua.LVAsString(text)
 				}
 
 				if preview := item.RawGetString("Preview"); preview != lua.LNil {
-					entry.Preview = string(preview.(lua.LString))
+					entry.Preview = lua.LVAsString(preview)
 				}
 
 				if preview := item.RawGetString("PreviewType"); preview != lua.LNil {
-					entry.PreviewType = string(preview.(lua.LString))
+					entry.PreviewType = lua.LVAsString(preview)
 				}
 
 				if subtext := item.RawGetString("Subtext"); subtext != lua.LNil {
-					entry.Subtext = string(subtext.(lua.LString))
+					entry.Subtext = lua.LVAsString(subtext)
 				}
 
 				if submenu := item.RawGetString("SubMenu"); submenu != lua.LNil {
-					entry.SubMenu = string(submenu.(lua.LString))
+					entry.SubMenu = lua.LVAsString(submenu)
 				}
 
 				if val := item.RawGetString("Value"); val != lua.LNil {
-					entry.Value = string(val.(lua.LString))
+					entry.Value = lua.LVAsString(val)
 				}
 
 				if icon := item.RawGetString("Icon"); icon != lua.LNil {
-					entry.Icon = string(icon.(lua.LString))
+					entry.Icon = lua.LVAsString(icon)
 				}
 
 				if actions := item.RawGet(lua.LString("Actions")); actions != lua.LNil {
@@ -301,43 +404,58 @@ func (m *Menu) CreateLuaEntries() {
 					}
 				}
 
-				identifier := entry.CreateIdentifier()
-
-				entry.Menu = m.Name
-
-				if entry.SubMenu != "" {
-					entry.Identifier = fmt.Sprintf("menus:%s:%s:%s", entry.SubMenu, entry.Menu, identifier)
-				} else if m.SubMenu != "" {
-					entry.Identifier = fmt.Sprintf("menus:%s:%s:%s", m.SubMenu, entry.Menu, identifier)
-				} else {
-					entry.Identifier = fmt.Sprintf("%s:%s", entry.Menu, identifier)
+				if inputs := item.RawGetString("Inputs"); inputs != lua.LNil {
+					entry.Inputs = luaInputs(inputs)
 				}
 
-				if entry.Preview != "" && entry.PreviewType == "" {
-					entry.PreviewType = "file"
+				if when := item.RawGetString("When"); when != lua.LNil {
+					entry.When = luaWhen(when)
 				}
 
+				m.identify(&entry)
+
 				res = append(res, entry)
 			}
 		})
 	}
 
-	m.Entries = res
+	return res, nil
+}
+
+// identify sets the identifier of an entry created at runtime.
+func (m *Menu) identify(entry *Entry) {
+	identifier := entry.CreateIdentifier()
+
+	entry.Menu = m.Name
+
+	if entry.SubMenu != "" {
+		entry.Identifier = fmt.Sprintf("menus:%s:%s:%s", entry.SubMenu, entry.Menu, identifier)
+	} else if m.SubMenu != "" {
+		entry.Identifier = fmt.Sprintf("menus:%s:%s:%s", m.SubMenu, entry.Menu, identifier)
+	} else {
+		entry.Identifier = fmt.Sprintf("%s:%s", entry.Menu, identifier)
+	}
+
+	if entry.Preview != "" && entry.PreviewType == "" {
+		entry.PreviewType = "file"
+	}
 }
 
 type Entry struct {
-	Text        string            `toml:"text" desc:"text for entry"`
-	Async       string            `toml:"async" desc:"if the text should be updated asynchronously based on the action"`
-	Subtext     string            `toml:"subtext" desc:"sub text for entry"`
-	Value       string            `toml:"value" desc:"value to be used for the action."`
-	Actions     map[string]string `toml:"actions" desc:"actions items can use"`
-	Terminal    bool              `toml:"terminal" desc:"runs action in terminal if true"`
-	Icon        string            `toml:"icon" desc:"icon for entry"`
-	SubMenu     string            `toml:"submenu" desc:"submenu to open, if has prefix 'dmenu:' it'll launch that dmenu"`
-	Preview     string            `toml:"preview" desc:"filepath for the preview"`
-	PreviewType string            `toml:"preview_type" desc:"type of the preview: text, file [default], command"`
-	Keywords    []string          `toml:"keywords" desc:"searchable keywords"`
-	State       []string          `toml:"state" desc:"state of an item, can be used to f.e. mark it as current"`
+	Text        string             `toml:"text" desc:"text for entry"`
+	Async       string             `toml:"async" desc:"if the text should be updated asynchronously based on the action"`
+	Subtext     string             `toml:"subtext" desc:"sub text for entry"`
+	Value       string             `toml:"value" desc:"value to be used for the action."`
+	Actions     map[string]string  `toml:"actions" desc:"actions items can use"`
+	Terminal    bool               `toml:"terminal" desc:"runs action in terminal if true"`
+	Icon        string             `toml:"icon" desc:"icon for entry"`
+	SubMenu     string             `toml:"submenu" desc:"submenu to open, if has prefix 'dmenu:' it'll launch that dmenu"`
+	Preview     string             `toml:"preview" desc:"filepath for the preview"`
+	PreviewType string             `toml:"preview_type" desc:"type of the preview: text, file [default], command"`
+	Keywords    []string           `toml:"keywords" desc:"searchable keywords"`
+	State       []string           `toml:"state" desc:"state of an item, can be used to f.e. mark it as current"`
+	Inputs      map[string][]Input `toml:"inputs" desc:"input fields per action, replace the ones of the menu"`
+	When        When               `toml:"when" desc:"conditions for showing the entry"`
 
 	Identifier string `toml:"-"`
 	Menu       string `toml:"-"`
@@ -352,14 +470,71 @@ var (
 	MenuConfigLoaded MenuConfig
 	menuname         = "menus"
 	Menus            = make(map[string]*Menu)
+
+	// menusMu guards Menus, as Lua menus are replaced when their script changes.
+	menusMu sync.RWMutex
 )
 
+// GetMenu returns the menu with the given name.
+func GetMenu(name string) (*Menu, bool) {
+	menusMu.RLock()
+	defer menusMu.RUnlock()
+
+	m, ok := Menus[name]
+
+	return m, ok
+}
+
+// AllMenus returns all loaded menus, sorted by name.
+func AllMenus() []*Menu {
+	menusMu.RLock()
+	defer menusMu.RUnlock()
+
+	res := make([]*Menu, 0, len(Menus))
+
+	for _, v := range Menus {
+		res = append(res, v)
+	}
+
+	slices.SortFunc(res, func(a, b *Menu) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return res
+}
+
+func setMenu(m *Menu) {
+	menusMu.Lock()
+	Menus[m.Name] = m
+	menusMu.Unlock()
+}
+
 func LoadMenus() {
+	loadMenuConfig()
+
+	if err := walkMenus(MenuConfigLoaded.Paths, func(path string) {
+		switch filepath.Ext(path) {
+		case ".toml":
+			createTomlMenu(path)
+		case ".lua":
+			createLuaMenu(path)
+		}
+	}); err != nil {
+		slog.Error(menuname, "walk", err)
+		os.Exit(1)
+	}
+}
+
+// loadMenuConfig loads the menus config and adds the default menu paths.
+func loadMenuConfig() {
 	MenuConfigLoaded = MenuConfig{
 		Config: Config{
 			MinScore: 10,
 		},
-		Paths: []string{},
+		Paths:            []string{},
+		LuaTimeout:       3000,
+		LuaCallStackSize: lua.CallStackSize,
+		LuaRegistrySize:  lua.RegistrySize,
 	}
 
 	LoadConfig(menuname, &MenuConfigLoaded)
@@ -371,13 +546,22 @@ func LoadMenus() {
 
 	installed := filepath.Join(xdg.DataHome, "elephant", "install")
 	MenuConfigLoaded.Paths = append(MenuConfigLoaded.Paths, installed)
+}
 
+// walkMenus calls fn for every menu definition in the given directories, or with the given files. Missing ones are skipped.
+func walkMenus(roots []string, fn func(path string)) error {
 	conf := fastwalk.Config{
 		Follow: true,
 	}
 
-	for _, root := range MenuConfigLoaded.Paths {
-		if _, err := os.Stat(root); err != nil {
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue
+		}
+
+		if !info.IsDir() {
+			fn(root)
 			continue
 		}
 
@@ -391,34 +575,71 @@ func LoadMenus() {
 			}
 
 			switch filepath.Ext(path) {
-			case ".toml":
-				createTomlMenu(path)
-			case ".lua":
-				createLuaMenu(path)
+			case ".toml", ".lua":
+				fn(path)
 			}
 
 			return nil
 		}); err != nil {
-			slog.Error(menuname, "walk", err)
-			os.Exit(1)
+			return err
 		}
 	}
+
+	return nil
 }
 
 func createLuaMenu(path string) {
-	m := Menu{}
-	m.IsLua = true
+	m := &Menu{
+		IsLua:   true,
+		LuaPath: path,
+		lua:     &luaMenu{},
+		dynamic: &dynamicMenu{},
+	}
 
-	b, err := os.ReadFile(path)
-	if err != nil {
-		slog.Error(m.Name, "lua read", err)
+	if err := m.loadLua(); err != nil {
+		slog.Error(menuname, "path", path, "error", err)
 		return
 	}
 
+	m.initEntries()
+
+	if m.Name == "" || m.NamePretty == "" {
+		slog.Error("menus", "path", path, "error", "missing Name or NamePretty")
+		return
+	}
+
+	setMenu(m)
+}
+
+// loadLua reads the script and the menu definition from its globals. The state is kept, Init runs on first use.
+func (m *Menu) loadLua() error {
+	b, err := os.ReadFile(m.LuaPath)
+	if err != nil {
+		return err
+	}
+
 	m.LuaString = string(b)
 
-	state := m.NewLuaState()
+	state, err := m.newLuaState()
+	if err != nil {
+		return err
+	}
+
+	if err := checkLuaGlobals(state); err != nil {
+		state.Close()
+		return err
+	}
+
+	m.lua.l = state
+	m.lua.initialized = false
+
+	m.readLuaGlobals(state)
 
+	return nil
+}
+
+// readLuaGlobals sets the menu definition from the script's globals.
+func (m *Menu) readLuaGlobals(state *lua.LState) {
 	if val := state.GetGlobal("Name"); val != lua.LNil {
 		m.Name = string(val.(lua.LString))
 	}
@@ -503,29 +724,218 @@ func createLuaMenu(path string) {
 		m.SubMenu = string(val.(lua.LString))
 	}
 
-	if m.Cache {
-		m.CreateLuaEntries()
+	if val := state.GetGlobal("Async"); val != lua.LNil {
+		m.Async = bool(val.(lua.LBool))
 	}
 
-	if m.Name == "" || m.NamePretty == "" {
-		slog.Error("menus", "path", path, "error", "missing Name or NamePretty")
+	if val := state.GetGlobal("RefreshInterval"); val != lua.LNil {
+		m.RefreshInterval = int(val.(lua.LNumber))
+	}
+
+	if val := state.GetGlobal("Timeout"); val != lua.LNil {
+		m.Timeout = int(val.(lua.LNumber))
+	}
+
+	if val := state.GetGlobal("Inputs"); val != lua.LNil {
+		m.Inputs = luaInputs(val)
+	}
+
+	if val := state.GetGlobal("When"); val != lua.LNil {
+		m.When = luaWhen(val)
+	}
+}
+
+// reloadLua re-runs a changed script with a new state and replaces the menu with the result.
+// If it fails, the menu keeps its old state. Readers of the old menu aren't affected.
+func (m *Menu) reloadLua() {
+	n := &Menu{
+		IsLua:   true,
+		LuaPath: m.LuaPath,
+		lua:     m.lua,
+		dynamic: m.dynamic,
+	}
+
+	m.lua.mu.Lock()
+
+	old := m.lua.l
+
+	if err := n.loadLua(); err != nil {
+		m.lua.mu.Unlock()
+
+		slog.Error(menuname, "reload", m.LuaPath, "error", err)
+		return
+	}
+
+	if n.Name != m.Name {
+		slog.Warn(menuname, "reload", m.LuaPath, "error", "renaming a menu requires a restart")
+		n.Name = m.Name
+	}
+
+	if old != nil {
+		old.Close()
+	}
+
+	m.lua.mu.Unlock()
+
+	// the last entries are served until the new ones are created.
+	n.Entries = m.CurrentEntries()
+
+	setMenu(n)
+
+	slog.Info(menuname, "reloaded", n.Name)
+
+	if n.Async {
+		n.RefreshEntries()
 		return
 	}
 
-	Menus[m.Name] = &m
+	go func() {
+		if n.Cache {
+			n.CreateLuaEntries()
+		}
+
+		MenuEntriesChanged <- n.Name
+	}()
 }
 
+var watchOnce sync.Once
+
+// WatchLuaMenus reloads Lua menus when their script changes and loads scripts added to the menu directories.
+func WatchLuaMenus() {
+	watchOnce.Do(func() {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			slog.Error(menuname, "watcher", err)
+			return
+		}
+
+		// editors often replace files, so the directories are watched instead of the scripts.
+		for _, root := range MenuConfigLoaded.Paths {
+			watchMenuDir(watcher, root, false)
+		}
+
+		go func() {
+			for {
+				select {
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return
+					}
+
+					if !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+						continue
+					}
+
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						if isMenuDir(event.Name) {
+							watchMenuDir(watcher, event.Name, true)
+						}
+
+						continue
+					}
+
+					if filepath.Ext(event.Name) != ".lua" || !isMenuDir(filepath.Dir(event.Name)) {
+						continue
+					}
+
+					found := false
+
+					for _, m := range AllMenus() {
+						if m.IsLua && m.LuaPath == event.Name {
+							found = true
+							m.reloadLua()
+						}
+					}
+
+					if !found {
+						createLuaMenu(event.Name)
+					}
+				case err, ok := <-watcher.Errors:
+					if !ok {
+						return
+					}
+
+					slog.Error(menuname, "watcher", err)
+				}
+			}
+		}()
+	})
+}
+
+// watchMenuDir watches a menu directory and its subdirectories. If it doesn't exist yet, its parent is watched to pick it up once created.
+// For directories created at runtime, the scripts already in them are loaded.
+func watchMenuDir(watcher *fsnotify.Watcher, root string, load bool) {
+	if _, err := os.Stat(root); err != nil {
+		if err := watcher.Add(filepath.Dir(root)); err != nil {
+			slog.Debug(menuname, "watcher", err)
+		}
+
+		return
+	}
+
+	conf := fastwalk.Config{
+		Follow: true,
+	}
+
+	if err := fastwalk.Walk(&conf, root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			if load && filepath.Ext(path) == ".lua" && !hasLuaMenu(path) {
+				createLuaMenu(path)
+			}
+
+			return nil
+		}
+
+		if err := watcher.Add(path); err != nil {
+			slog.Error(menuname, "watcher", err)
+		}
+
+		return nil
+	}); err != nil {
+		slog.Error(menuname, "watcher", err)
+	}
+}
+
+// isMenuDir reports whether the directory is one of the menu directories or inside of one.
+func isMenuDir(dir string) bool {
+	return slices.ContainsFunc(MenuConfigLoaded.Paths, func(root string) bool {
+		return dir == root || strings.HasPrefix(dir, root+string(filepath.Separator))
+	})
+}
+
+func hasLuaMenu(path string) bool {
+	return slices.ContainsFunc(AllMenus(), func(m *Menu) bool {
+		return m.IsLua && m.LuaPath == path
+	})
+}
+
+// createTomlMenu registers a menu definition. Invalid ones are skipped, `elephant menus validate` shows why.
 func createTomlMenu(path string) {
 	m := Menu{}
 
 	b, err := os.ReadFile(path)
 	if err != nil {
 		slog.Error(menuname, "setup", err)
+		return
 	}
 
-	err = toml.Unmarshal(b, &m)
-	if err != nil {
-		slog.Error(menuname, "setup", err)
+	if err := toml.Unmarshal(b, &m); err != nil {
+		if p := tomlProblem(path, err); p != nil {
+			slog.Error(menuname, "setup", p.String())
+		} else {
+			slog.Error(menuname, "setup", err, "path", path)
+		}
+
+		return
+	}
+
+	if m.Name == "" || m.NamePretty == "" {
+		slog.Error(menuname, "path", path, "error", "missing name or name_pretty")
+		return
 	}
 
 	for k, v := range m.Entries {
@@ -541,5 +951,10 @@ func createTomlMenu(path string) {
 		}
 	}
 
-	Menus[m.Name] = &m
+	if m.Generator != "" {
+		m.dynamic = &dynamicMenu{}
+		m.initEntries()
+	}
+
+	setMenu(&m)
 }
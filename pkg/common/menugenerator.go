@@ -0,0 +1,214 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Mapping selects the fields of a generator's output used for entries.
+// For the json format these are keys, for tsv columns starting at 1. Lines are used as text and value.
+type Mapping struct {
+	Text    string `toml:"text" desc:"text of the entry" default:"text or 1"`
+	Subtext string `toml:"subtext" desc:"subtext of the entry" default:"subtext"`
+	Value   string `toml:"value" desc:"value of the entry, falls back to the text" default:"value"`
+	Icon    string `toml:"icon" desc:"icon of the entry" default:"icon"`
+	Preview string `toml:"preview" desc:"preview of the entry" default:"preview"`
+}
+
+// createGeneratedEntries replaces the entries with the ones parsed from the generator's output.
+func (m *Menu) createGeneratedEntries() error {
+	err := m.generate()
+	if err != nil {
+		err = fmt.Errorf("generator: %w", err)
+	}
+
+	m.setErr(err)
+
+	return err
+}
+
+func (m *Menu) generate() error {
+	ctx := context.Background()
+
+	if timeout := m.timeout(); timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", m.Generator)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var res []Entry
+
+	switch m.Format {
+	case "", "lines":
+		res = parseLines(out)
+	case "tsv":
+		res, err = m.Mapping.parseTSV(out)
+	case "json":
+		res, err = m.Mapping.parseJSON(out)
+	default:
+		err = fmt.Errorf("unknown format %q", m.Format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for k := range res {
+		if res[k].Value == "" {
+			res[k].Value = res[k].Text
+		}
+
+		m.identify(&res[k])
+	}
+
+	m.setEntries(res)
+
+	return nil
+}
+
+func parseLines(out []byte) []Entry {
+	res := []Entry{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.TrimSpace(line) != "" {
+			res = append(res, Entry{Text: line, Value: line})
+		}
+	}
+
+	return res
+}
+
+func (mp Mapping) parseTSV(out []byte) ([]Entry, error) {
+	column := func(fields []string, mapping, fallback string) (string, error) {
+		if mapping == "" {
+			mapping = fallback
+		}
+
+		if mapping == "" {
+			return "", nil
+		}
+
+		i, err := strconv.Atoi(mapping)
+		if err != nil || i < 1 {
+			return "", fmt.Errorf("invalid column %q", mapping)
+		}
+
+		if i > len(fields) {
+			return "", nil
+		}
+
+		return fields[i-1], nil
+	}
+
+	res := []Entry{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+
+		var e Entry
+		var err error
+
+		for _, v := range []struct {
+			dst      *string
+			mapping  string
+			fallback string
+		}{
+			{&e.Text, mp.Text, "1"},
+			{&e.Subtext, mp.Subtext, ""},
+			{&e.Value, mp.Value, ""},
+			{&e.Icon, mp.Icon, ""},
+			{&e.Preview, mp.Preview, ""},
+		} {
+			if *v.dst, err = column(fields, v.mapping, v.fallback); err != nil {
+				return nil, err
+			}
+		}
+
+		res = append(res, e)
+	}
+
+	return res, nil
+}
+
+// parseJSON reads an array of objects, or one object per line.
+func (mp Mapping) parseJSON(out []byte) ([]Entry, error) {
+	items := []map[string]any{}
+
+	if err := json.Unmarshal(out, &items); err != nil {
+		items = items[:0]
+
+		dec := json.NewDecoder(bytes.NewReader(out))
+
+		for dec.More() {
+			var item map[string]any
+
+			if err := dec.Decode(&item); err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	key := func(item map[string]any, mapping, fallback string) string {
+		if mapping == "" {
+			mapping = fallback
+		}
+
+		val, ok := item[mapping]
+		if !ok || val == nil {
+			return ""
+		}
+
+		if str, ok := val.(string); ok {
+			return str
+		}
+
+		return fmt.Sprint(val)
+	}
+
+	res := []Entry{}
+
+	for _, item := range items {
+		res = append(res, Entry{
+			Text:    key(item, mp.Text, "text"),
+			Subtext: key(item, mp.Subtext, "subtext"),
+			Value:   key(item, mp.Value, "value"),
+			Icon:    key(item, mp.Icon, "icon"),
+			Preview: key(item, mp.Preview, "preview"),
+		})
+	}
+
+	return res, nil
+}
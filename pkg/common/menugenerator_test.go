@@ -0,0 +1,102 @@
+package common
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGeneratedEntries(t *testing.T) {
+	MenuConfigLoaded = MenuConfig{LuaTimeout: 3000}
+
+	tests := []struct {
+		format    string
+		mapping   Mapping
+		generator string
+		want      []Entry
+	}{
+		{"lines", Mapping{}, `printf 'a\n\nb\n'`, []Entry{{Text: "a", Value: "a"}, {Text: "b", Value: "b"}}},
+		{"tsv", Mapping{Text: "2", Value: "1"}, `printf '1\tone\n2\ttwo\n'`, []Entry{{Text: "one", Value: "1"}, {Text: "two", Value: "2"}}},
+		{"json", Mapping{Text: "name"}, `echo '[{"name":"x","subtext":"y","value":3}]'`, []Entry{{Text: "x", Subtext: "y", Value: "3"}}},
+		{"json", Mapping{Text: "name"}, `printf '{"name":"x"}\n{"name":"y"}\n'`, []Entry{{Text: "x", Value: "x"}, {Text: "y", Value: "y"}}},
+	}
+
+	for _, tt := range tests {
+		m := &Menu{Name: "gen", Generator: tt.generator, Format: tt.format, Mapping: tt.mapping, dynamic: &dynamicMenu{}}
+
+		if err := m.CreateEntries(); err != nil {
+			t.Fatalf("%s: %v", tt.generator, err)
+		}
+
+		if len(m.Entries) != len(tt.want) {
+			t.Fatalf("%s: got %d entries, want %d", tt.generator, len(m.Entries), len(tt.want))
+		}
+
+		for k, v := range tt.want {
+			got := m.Entries[k]
+
+			if got.Text != v.Text || got.Subtext != v.Subtext || got.Value != v.Value || got.Menu != "gen" || got.Identifier == "" {
+				t.Errorf("%s: got %+v, want %+v", tt.generator, got, v)
+			}
+		}
+	}
+
+	m := &Menu{Name: "gen", Generator: "exit 1", dynamic: &dynamicMenu{}}
+
+	if err := m.CreateEntries(); err == nil || m.LastError() == nil {
+		t.Error("failing generator: want error")
+	}
+}
+
+func TestGeneratedEntriesConcurrent(t *testing.T) {
+	MenuConfigLoaded = MenuConfig{LuaTimeout: 3000}
+
+	m := &Menu{Name: "gen", Generator: `printf 'a\nb\n'`, dynamic: &dynamicMenu{}}
+
+	var wg sync.WaitGroup
+
+	for range 8 {
+		wg.Go(func() {
+			if err := m.CreateEntries(); err != nil {
+				t.Error(err)
+			}
+
+			for _, v := range m.CurrentEntries() {
+				_ = v.Text
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if len(m.CurrentEntries()) != 2 {
+		t.Fatalf("got %d entries, want 2", len(m.CurrentEntries()))
+	}
+}
+
+func TestGeneratedEntriesRefresh(t *testing.T) {
+	MenuConfigLoaded = MenuConfig{LuaTimeout: 3000}
+
+	m := &Menu{Name: "refreshed", Generator: "echo a", dynamic: &dynamicMenu{}}
+
+	m.RefreshEntries()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case name := <-MenuEntriesChanged:
+			if name != m.Name {
+				continue
+			}
+
+			if len(m.CurrentEntries()) != 1 {
+				t.Fatalf("got %d entries, want 1", len(m.CurrentEntries()))
+			}
+
+			return
+		case <-timeout:
+			t.Fatal("no entries changed notification")
+		}
+	}
+}
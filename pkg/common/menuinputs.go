@@ -0,0 +1,187 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// InputTypes are the types of input fields a frontend can render.
+var InputTypes = []string{"text", "number", "choice", "file", "password"}
+
+// Input is a field a frontend asks for before activating an action. Its value can be used as %ARG:name%.
+type Input struct {
+	Name     string   `toml:"name" desc:"key of the value in the arguments, use it as %ARG:name%"`
+	Label    string   `toml:"label" desc:"label shown by the frontend" default:"name"`
+	Type     string   `toml:"type" desc:"text, number, choice, file or password" default:"text"`
+	Choices  []string `toml:"choices" desc:"values to choose from, for type choice"`
+	Default  string   `toml:"default" desc:"value used if none is given"`
+	Required bool     `toml:"required" desc:"activation fails without a value" default:"false"`
+}
+
+// ActionInputs returns the inputs of every action of an entry. Inputs of the entry replace the ones of the menu.
+func (m *Menu) ActionInputs(e *Entry) map[string][]Input {
+	res := make(map[string][]Input)
+
+	for k, v := range m.Inputs {
+		res[k] = v
+	}
+
+	if e != nil {
+		for k, v := range e.Inputs {
+			res[k] = v
+		}
+	}
+
+	return res
+}
+
+// InputValues reads the values of the inputs from the arguments of an activation, usually a json object.
+// If the arguments aren't one and there's a single input, they are used as its value.
+func InputValues(inputs []Input, args string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	if len(inputs) == 0 {
+		return values, nil
+	}
+
+	raw := make(map[string]any)
+
+	if err := json.Unmarshal([]byte(args), &raw); err != nil {
+		if len(inputs) != 1 {
+			return nil, fmt.Errorf("arguments have to be a json object: %w", err)
+		}
+
+		raw = map[string]any{inputs[0].Name: args}
+	}
+
+	for _, v := range inputs {
+		val := v.Default
+
+		if r, ok := raw[v.Name]; ok && r != nil && r != "" {
+			val = fmt.Sprint(r)
+		}
+
+		if err := v.check(val); err != nil {
+			return nil, err
+		}
+
+		values[v.Name] = val
+	}
+
+	return values, nil
+}
+
+// InputPlaceholders returns the values for %ARG:name% placeholders in commands and the environment the command needs.
+// Text and file values are quoted for sh, so they can't break the command. Passwords are passed in the environment
+// as ELEPHANT_ARG_NAME and the placeholder refers to the variable, so they don't show up on the command line of sh.
+// Numbers and choices are checked already and used as they are.
+func InputPlaceholders(inputs []Input, values map[string]string) (map[string]string, []string) {
+	res := make(map[string]string)
+	env := []string{}
+
+	for _, v := range inputs {
+		val, ok := values[v.Name]
+		if !ok {
+			continue
+		}
+
+		key := "ARG:" + v.Name
+
+		switch v.Type {
+		case "number", "choice":
+			res[key] = val
+		case "password":
+			name := inputEnv(v.Name)
+			env = append(env, name+"="+val)
+			res[key] = `"$` + name + `"`
+		default:
+			res[key] = ShellQuote(val)
+		}
+	}
+
+	return res, env
+}
+
+// inputEnv returns the name of the environment variable for an input, f.e. ELEPHANT_ARG_API_KEY for "api-key".
+func inputEnv(name string) string {
+	return "ELEPHANT_ARG_" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+func (i Input) check(val string) error {
+	if val == "" {
+		if i.Required {
+			return fmt.Errorf("%s: value required", i.Name)
+		}
+
+		return nil
+	}
+
+	switch i.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			return fmt.Errorf("%s: %q isn't a number", i.Name, val)
+		}
+	case "choice":
+		if !slices.Contains(i.Choices, val) {
+			return fmt.Errorf("%s: %q isn't one of %s", i.Name, val, strings.Join(i.Choices, ", "))
+		}
+	}
+
+	return nil
+}
+
+// luaInputs reads inputs per action from a Lua table, f.e. { start = { { Name = "minutes", Type = "number" } } }.
+func luaInputs(val lua.LValue) map[string][]Input {
+	table, ok := val.(*lua.LTable)
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string][]Input)
+
+	table.ForEach(func(action, fields lua.LValue) {
+		list, ok := fields.(*lua.LTable)
+		if !ok {
+			return
+		}
+
+		list.ForEach(func(_, field lua.LValue) {
+			f, ok := field.(*lua.LTable)
+			if !ok {
+				return
+			}
+
+			i := Input{
+				Name:     lua.LVAsString(f.RawGetString("Name")),
+				Label:    lua.LVAsString(f.RawGetString("Label")),
+				Type:     lua.LVAsString(f.RawGetString("Type")),
+				Default:  lua.LVAsString(f.RawGetString("Default")),
+				Required: lua.LVAsBool(f.RawGetString("Required")),
+			}
+
+			if choices, ok := f.RawGetString("Choices").(*lua.LTable); ok {
+				choices.ForEach(func(_, c lua.LValue) {
+					i.Choices = append(i.Choices, lua.LVAsString(c))
+				})
+			}
+
+			res[action.String()] = append(res[action.String()], i)
+		})
+	})
+
+	return res
+}
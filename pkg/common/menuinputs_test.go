@@ -0,0 +1,64 @@
+package common
+
+import "testing"
+
+func TestInputValues(t *testing.T) {
+	inputs := []Input{
+		{Name: "minutes", Type: "number", Required: true},
+		{Name: "sound", Type: "choice", Choices: []string{"bell", "none"}, Default: "bell"},
+	}
+
+	tests := []struct {
+		args    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{`{"minutes": 5}`, map[string]string{"minutes": "5", "sound": "bell"}, false},
+		{`{"minutes": "10", "sound": "none"}`, map[string]string{"minutes": "10", "sound": "none"}, false},
+		{`{"sound": "none"}`, nil, true},
+		{`{"minutes": "ten"}`, nil, true},
+		{`{"minutes": 1, "sound": "horn"}`, nil, true},
+		{`5`, nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := InputValues(inputs, tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("InputValues(%s) error = %v", tt.args, err)
+			continue
+		}
+
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("InputValues(%s)[%s] = %q, want %q", tt.args, k, got[k], v)
+			}
+		}
+	}
+
+	got, err := InputValues(inputs[:1], "15")
+	if err != nil || got["minutes"] != "15" {
+		t.Errorf("InputValues with a single input = %v, %v", got, err)
+	}
+}
+
+func TestInputPlaceholders(t *testing.T) {
+	inputs := []Input{
+		{Name: "minutes", Type: "number"},
+		{Name: "message"},
+		{Name: "api-key", Type: "password"},
+	}
+
+	values := map[string]string{"minutes": "5", "message": "it's $(done)", "api-key": "secret"}
+
+	placeholders, env := InputPlaceholders(inputs, values)
+
+	cmd := Expand("sleep %ARG:minutes%m && notify-send %ARG:message% %ARG:api-key%", placeholders)
+
+	if want := `sleep 5m && notify-send 'it'\''s $(done)' "$ELEPHANT_ARG_API_KEY"`; cmd != want {
+		t.Errorf("got %s, want %s", cmd, want)
+	}
+
+	if len(env) != 1 || env[0] != "ELEPHANT_ARG_API_KEY=secret" {
+		t.Errorf("got env %v", env)
+	}
+}
@@ -0,0 +1,172 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// MenuEntriesChanged receives the name of menus whose entries were re-created in the background, by a generator,
+// a Lua script or refresh(). Subscribers are told to query again, the menu isn't opened.
+var MenuEntriesChanged = make(chan string)
+
+var (
+	menuQuery    = make(map[string]string)
+	menuQueryMut sync.Mutex
+)
+
+// SetMenuQuery stores the query a menu is currently used with, so Lua scripts can read it.
+func SetMenuQuery(menu, query string) {
+	menuQueryMut.Lock()
+	menuQuery[menu] = query
+	menuQueryMut.Unlock()
+}
+
+// setLuaHostFunctions registers the functions a Lua menu can call besides its state.
+// Functions running commands or reading files are optional, see optionalLuaFunctions.
+func (m *Menu) setLuaHostFunctions(l *lua.LState) {
+	l.SetGlobal("clipboard", l.NewFunction(luaClipboard))
+	l.SetGlobal("query", l.NewFunction(m.luaQuery))
+	l.SetGlobal("log", l.NewFunction(m.luaLog))
+	l.SetGlobal("refresh", l.NewFunction(m.luaRefresh))
+}
+
+// luaRun runs a shell command and returns stdout, the exit code and stderr. An optional second argument is passed as stdin.
+// The command is killed if the call into the script times out.
+func luaRun(L *lua.LState) int {
+	ctx := L.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", L.CheckString(1))
+
+	if L.GetTop() > 1 {
+		cmd.Stdin = strings.NewReader(L.CheckString(2))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	code := 0
+
+	if err := cmd.Run(); err != nil {
+		var exit *exec.ExitError
+
+		if errors.As(err, &exit) {
+			code = exit.ExitCode()
+		} else {
+			code = -1
+			stderr.WriteString(err.Error())
+		}
+	}
+
+	L.Push(lua.LString(stdout.String()))
+	L.Push(lua.LNumber(code))
+	L.Push(lua.LString(stderr.String()))
+
+	return 3
+}
+
+func luaClipboard(L *lua.LState) int {
+	L.Push(lua.LString(ClipboardText()))
+	return 1
+}
+
+func luaSetClipboard(L *lua.LState) int {
+	cmd := exec.Command("wl-copy")
+	cmd.Stdin = strings.NewReader(L.CheckString(1))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		L.Push(lua.LString(strings.TrimSpace(string(out))))
+		return 1
+	}
+
+	return 0
+}
+
+// luaNotify sends a desktop notification with a summary and an optional body.
+func luaNotify(L *lua.LState) int {
+	args := []string{L.CheckString(1)}
+
+	if body := L.OptString(2, ""); body != "" {
+		args = append(args, body)
+	}
+
+	if out, err := exec.Command("notify-send", args...).CombinedOutput(); err != nil {
+		L.Push(lua.LString(strings.TrimSpace(string(out))))
+		return 1
+	}
+
+	return 0
+}
+
+// luaReadFile returns the content of a file, or nil and the error.
+func luaReadFile(L *lua.LState) int {
+	path := L.CheckString(1)
+
+	if after, ok := strings.CutPrefix(path, "~/"); ok {
+		home, _ := os.UserHomeDir()
+		path = home + "/" + after
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		L.Push(lua.LNil)
+		L.Push(lua.LString(err.Error()))
+		return 2
+	}
+
+	L.Push(lua.LString(string(b)))
+	return 1
+}
+
+func (m *Menu) luaQuery(L *lua.LState) int {
+	menuQueryMut.Lock()
+	L.Push(lua.LString(menuQuery[m.Name]))
+	menuQueryMut.Unlock()
+
+	return 1
+}
+
+// luaLog logs through slog, f.e. log("error", "something failed"). Without a known level the first argument is logged as info.
+func (m *Menu) luaLog(L *lua.LState) int {
+	level := L.CheckString(1)
+	msg := L.OptString(2, "")
+
+	switch level {
+	case "debug":
+		slog.Debug(m.Name, "lua", msg)
+	case "info":
+		slog.Info(m.Name, "lua", msg)
+	case "warn":
+		slog.Warn(m.Name, "lua", msg)
+	case "error":
+		slog.Error(m.Name, "lua", msg)
+	default:
+		slog.Info(m.Name, "lua", strings.TrimSpace(level+" "+msg))
+	}
+
+	return 0
+}
+
+// luaRefresh re-creates the entries of a cached menu and notifies subscribers of the menu.
+func (m *Menu) luaRefresh(L *lua.LState) int {
+	go func() {
+		if m.Cache {
+			m.CreateLuaEntries()
+		}
+
+		MenuEntriesChanged <- m.Name
+	}()
+
+	return 0
+}
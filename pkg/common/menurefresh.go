@@ -0,0 +1,135 @@
+package common
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// dynamicMenu is the state of a menu creating its entries at runtime. It's shared by copies of the menu, f.e. on reload.
+type dynamicMenu struct {
+	refreshing atomic.Bool
+
+	// create serializes creating the entries, so concurrent queries don't run the generator or script in parallel.
+	create sync.Mutex
+
+	// mu guards the error and the entries of the menu, which are replaced as a whole.
+	mu  sync.Mutex
+	err error
+}
+
+// Dynamic reports whether the entries are created by a Lua script or a generator.
+func (m *Menu) Dynamic() bool {
+	return m.IsLua || m.Generator != ""
+}
+
+// CreateEntries re-creates the entries of a dynamic menu. On error, the entries are kept.
+func (m *Menu) CreateEntries() error {
+	if m.dynamic != nil {
+		m.dynamic.create.Lock()
+		defer m.dynamic.create.Unlock()
+	}
+
+	if m.IsLua {
+		return m.CreateLuaEntries()
+	}
+
+	return m.createGeneratedEntries()
+}
+
+// CurrentEntries returns the current entries. The slice isn't modified afterwards, new entries replace it.
+func (m *Menu) CurrentEntries() []Entry {
+	if m.dynamic == nil {
+		return m.Entries
+	}
+
+	m.dynamic.mu.Lock()
+	defer m.dynamic.mu.Unlock()
+
+	return m.Entries
+}
+
+func (m *Menu) setEntries(entries []Entry) {
+	if m.dynamic == nil {
+		m.Entries = entries
+		return
+	}
+
+	m.dynamic.mu.Lock()
+	m.Entries = entries
+	m.dynamic.mu.Unlock()
+}
+
+// LastError returns the error of the last attempt to create entries or to call into the script, if it failed.
+func (m *Menu) LastError() error {
+	if m.dynamic == nil {
+		return nil
+	}
+
+	m.dynamic.mu.Lock()
+	defer m.dynamic.mu.Unlock()
+
+	return m.dynamic.err
+}
+
+func (m *Menu) setErr(err error) {
+	if m.dynamic == nil {
+		return
+	}
+
+	m.dynamic.mu.Lock()
+	m.dynamic.err = err
+	m.dynamic.mu.Unlock()
+}
+
+// initEntries creates the entries of a cached menu when it's loaded.
+func (m *Menu) initEntries() {
+	switch {
+	case !m.Cache:
+	case m.Async:
+		m.RefreshEntries()
+	default:
+		m.CreateEntries()
+	}
+}
+
+// RefreshEntries re-creates the entries in the background, unless that's already happening,
+// and notifies subscribers of the menu once they arrive. Until then, the last entries are served.
+func (m *Menu) RefreshEntries() {
+	if m.dynamic == nil || !m.dynamic.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		err := m.CreateEntries()
+		m.dynamic.refreshing.Store(false)
+
+		if err == nil {
+			MenuEntriesChanged <- m.Name
+		}
+	}()
+}
+
+var refreshOnce sync.Once
+
+// RefreshMenus periodically re-creates the entries of menus with a refresh interval.
+// The interval is read on every tick, so it can be changed by reloading a script.
+func RefreshMenus() {
+	refreshOnce.Do(func() {
+		go func() {
+			last := make(map[string]time.Time)
+
+			for now := range time.Tick(time.Second) {
+				for _, m := range AllMenus() {
+					if !m.Dynamic() || m.RefreshInterval <= 0 || now.Sub(last[m.Name]) < time.Duration(m.RefreshInterval)*time.Second {
+						continue
+					}
+
+					last[m.Name] = now
+
+					m.RefreshEntries()
+				}
+			}
+		}()
+	})
+}
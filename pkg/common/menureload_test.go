@@ -0,0 +1,47 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestReloadLua(t *testing.T) {
+	MenuConfigLoaded = MenuConfig{LuaTimeout: 3000, LuaCallStackSize: lua.CallStackSize, LuaRegistrySize: lua.RegistrySize}
+
+	path := filepath.Join(t.TempDir(), "reload.lua")
+
+	write := func(pretty string) {
+		script := `Name = "reload"
+NamePretty = "` + pretty + `"
+function GetEntries() return { { Text = "a" } } end
+`
+
+		if err := os.WriteFile(path, []byte(script), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("Old")
+	createLuaMenu(path)
+
+	old, ok := GetMenu("reload")
+	if !ok {
+		t.Fatal("menu not loaded")
+	}
+
+	write("New")
+	old.reloadLua()
+
+	m, _ := GetMenu("reload")
+
+	if m == old || old.NamePretty != "Old" || m.NamePretty != "New" {
+		t.Fatalf("got %q and %q, want the old menu untouched and a new one", old.NamePretty, m.NamePretty)
+	}
+
+	if err := m.CreateLuaEntries(); err != nil || len(m.Entries) != 1 {
+		t.Fatalf("got %v %d, want 1 entry", err, len(m.Entries))
+	}
+}
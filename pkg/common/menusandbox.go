@@ -0,0 +1,185 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+var (
+	ErrNoLuaState = errors.New("no lua state")
+	ErrLuaTimeout = errors.New("lua call timed out")
+)
+
+// safeLuaLibs are always opened, optionalLuaLibs only if lua_restrict_libs is disabled or the menu lists them in Libraries.
+// RegistryMaxSize only limits the slots of the data stack, memory used by tables and strings isn't limited.
+var (
+	safeLuaLibs = map[string]lua.LGFunction{
+		lua.BaseLibName:      lua.OpenBase,
+		lua.TabLibName:       lua.OpenTable,
+		lua.StringLibName:    lua.OpenString,
+		lua.MathLibName:      lua.OpenMath,
+		lua.CoroutineLibName: lua.OpenCoroutine,
+	}
+	optionalLuaLibs = map[string]lua.LGFunction{
+		lua.LoadLibName:    openLuaPackage,
+		lua.IoLibName:      lua.OpenIo,
+		lua.OsLibName:      lua.OpenOs,
+		lua.DebugLibName:   lua.OpenDebug,
+		lua.ChannelLibName: lua.OpenChannel,
+	}
+
+	// optionalLuaFunctions are host functions able to run commands or read files, restricted like optionalLuaLibs.
+	optionalLuaFunctions = map[string]lua.LGFunction{
+		"run":          luaRun,
+		"readFile":     luaReadFile,
+		"setClipboard": luaSetClipboard,
+		"notify":       luaNotify,
+	}
+
+	// luaFileFunctions are base functions loading files from disk, they are only set along with the package library.
+	luaFileFunctions = []string{"dofile", "loadfile", "require", "module"}
+)
+
+// luaFileFunctionsKey is the registry field holding luaFileFunctions until the package library is opened.
+const luaFileFunctionsKey = "elephant_file_functions"
+
+// newSandboxedState creates a Lua state limited by the menus config, with only the safe libraries opened.
+func newSandboxedState() *lua.LState {
+	l := lua.NewState(lua.Options{
+		CallStackSize:   MenuConfigLoaded.LuaCallStackSize,
+		RegistrySize:    MenuConfigLoaded.LuaRegistrySize,
+		RegistryMaxSize: MenuConfigLoaded.LuaRegistrySize,
+		SkipOpenLibs:    true,
+	})
+
+	for k, v := range safeLuaLibs {
+		openLuaLib(l, k, v)
+	}
+
+	stash := l.NewTable()
+
+	for _, v := range luaFileFunctions {
+		stash.RawSetString(v, l.GetGlobal(v))
+		l.SetGlobal(v, lua.LNil)
+	}
+
+	l.SetField(l.Get(lua.RegistryIndex), luaFileFunctionsKey, stash)
+
+	return l
+}
+
+// openLuaPackage opens the package library and sets the base functions loading files again.
+// Libraries opened before are kept in package.loaded, so requiring them doesn't look for files.
+func openLuaPackage(l *lua.LState) int {
+	opened, _ := l.GetField(l.Get(lua.RegistryIndex), "_LOADED").(*lua.LTable)
+
+	n := lua.OpenPackage(l)
+
+	if loaded, ok := l.GetField(l.Get(lua.RegistryIndex), "_LOADED").(*lua.LTable); ok && opened != nil {
+		opened.ForEach(func(k, v lua.LValue) {
+			loaded.RawSet(k, v)
+		})
+	}
+
+	if stash, ok := l.GetField(l.Get(lua.RegistryIndex), luaFileFunctionsKey).(*lua.LTable); ok {
+		stash.ForEach(func(k, v lua.LValue) {
+			l.SetGlobal(k.String(), v)
+		})
+	}
+
+	return n
+}
+
+// openLuaLibs opens the given optional libraries and registers the given optional host functions, unknown names are ignored.
+func openLuaLibs(l *lua.LState, libs []string) {
+	for k, v := range optionalLuaLibs {
+		if slices.Contains(libs, k) {
+			openLuaLib(l, k, v)
+		}
+	}
+
+	for k, v := range optionalLuaFunctions {
+		if slices.Contains(libs, k) {
+			l.SetGlobal(k, l.NewFunction(v))
+		}
+	}
+}
+
+// requestedLuaLibs returns the libraries listed in the script's Libraries global.
+func requestedLuaLibs(l *lua.LState) []string {
+	libs := []string{}
+
+	if val, ok := l.GetGlobal("Libraries").(*lua.LTable); ok {
+		val.ForEach(func(_, value lua.LValue) {
+			libs = append(libs, value.String())
+		})
+	}
+
+	return libs
+}
+
+func openLuaLib(l *lua.LState, name string, fn lua.LGFunction) {
+	l.Push(l.NewFunction(fn))
+	l.Push(lua.LString(name))
+	l.Call(1, 0)
+}
+
+// timeout returns the time a single call into the script or the generator may take, set by the menu's timeout or lua_timeout.
+func (m *Menu) timeout() time.Duration {
+	if m.Timeout > 0 {
+		return time.Duration(m.Timeout) * time.Millisecond
+	}
+
+	return time.Duration(MenuConfigLoaded.LuaTimeout) * time.Millisecond
+}
+
+// callLua runs fn with a deadline. Lua code is interrupted once it's exceeded, while calls blocking in Go, f.e. os.execute,
+// are left running. In both cases the state can't be used anymore and is closed as soon as fn returns.
+// A panic in fn is returned as an error, so a broken script can't take down the service.
+func (m *Menu) callLua(l *lua.LState, fn func() error) error {
+	timeout := m.timeout()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	}
+
+	defer cancel()
+
+	l.SetContext(ctx)
+
+	done := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("%v", r)
+			}
+		}()
+
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		if ctx.Err() == nil {
+			l.RemoveContext()
+			return err
+		}
+
+		l.Close()
+	case <-ctx.Done():
+		go func() {
+			<-done
+			l.Close()
+		}()
+	}
+
+	return fmt.Errorf("%w after %s", ErrLuaTimeout, timeout)
+}
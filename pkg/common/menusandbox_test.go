@@ -0,0 +1,32 @@
+package common
+
+import (
+	"testing"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+func TestSandboxFileFunctions(t *testing.T) {
+	MenuConfigLoaded = MenuConfig{LuaCallStackSize: lua.CallStackSize, LuaRegistrySize: lua.RegistrySize}
+
+	l := newSandboxedState()
+	defer l.Close()
+
+	for _, v := range append(luaFileFunctions, lua.LoadLibName) {
+		if l.GetGlobal(v) != lua.LNil {
+			t.Fatalf("%s is set in a sandboxed state", v)
+		}
+	}
+
+	openLuaLibs(l, []string{lua.LoadLibName})
+
+	for _, v := range append(luaFileFunctions, lua.LoadLibName) {
+		if l.GetGlobal(v) == lua.LNil {
+			t.Fatalf("%s isn't set after opening package", v)
+		}
+	}
+
+	if err := l.DoString(`assert(require("string").upper("a") == "A")`); err != nil {
+		t.Fatal(err)
+	}
+}
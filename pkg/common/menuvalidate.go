@@ -0,0 +1,548 @@
+package common
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// luaGlobalTypes are the types of the globals defining a Lua menu.
+var luaGlobalTypes = map[string]lua.LValueType{
+	"Name":                 lua.LTString,
+	"NamePretty":           lua.LTString,
+	"Description":          lua.LTString,
+	"Icon":                 lua.LTString,
+	"Action":               lua.LTString,
+	"Parent":               lua.LTString,
+	"SubMenu":              lua.LTString,
+	"HideFromProviderlist": lua.LTBool,
+	"SearchName":           lua.LTBool,
+	"Cache":                lua.LTBool,
+	"Terminal":             lua.LTBool,
+	"FixedOrder":           lua.LTBool,
+	"History":              lua.LTBool,
+	"HistoryWhenEmpty":     lua.LTBool,
+	"Async":                lua.LTBool,
+	"MinScore":             lua.LTNumber,
+	"RefreshInterval":      lua.LTNumber,
+	"Timeout":              lua.LTNumber,
+	"Inputs":               lua.LTTable,
+	"When":                 lua.LTTable,
+	"Actions":              lua.LTTable,
+	"Keywords":             lua.LTTable,
+	"Libraries":            lua.LTTable,
+	"GetEntries":           lua.LTFunction,
+	"Init":                 lua.LTFunction,
+}
+
+// checkLuaGlobals makes sure the globals defining the menu have the expected types, so they can be read safely.
+func checkLuaGlobals(state *lua.LState) error {
+	errs := []error{}
+
+	for k, v := range luaGlobalTypes {
+		if t := state.GetGlobal(k).Type(); t != lua.LTNil && t != v {
+			errs = append(errs, fmt.Errorf("%s: expected %s, got %s", k, v, t))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MenuProblem is an error in a menu definition. Line and Column are 0 if the location isn't known.
+type MenuProblem struct {
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p MenuProblem) String() string {
+	switch {
+	case p.Line == 0:
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	case p.Column == 0:
+		return fmt.Sprintf("%s:%d: %s", p.Path, p.Line, p.Message)
+	default:
+		return fmt.Sprintf("%s:%d:%d: %s", p.Path, p.Line, p.Column, p.Message)
+	}
+}
+
+// parsedMenu is a menu definition read without running it.
+type parsedMenu struct {
+	menu Menu
+	path string
+
+	// line returns the location of a key, optionally with the given value. Lua menus only support globals.
+	line func(key, value string) (int, int)
+
+	// functions are the global functions of a Lua menu, including globals which might be one.
+	functions []string
+
+	// dynamicName is set if the name of a Lua menu isn't a valid constant, so it can't be checked.
+	dynamicName bool
+}
+
+// ValidateMenus checks the menu definitions in the given files or directories, or in the configured menu paths if none are given.
+// Lua scripts are only parsed, not run.
+func ValidateMenus(paths ...string) []MenuProblem {
+	loadMenuConfig()
+
+	problems := []MenuProblem{}
+
+	parseAll := func(roots []string) ([]*parsedMenu, []MenuProblem, error) {
+		res := []*parsedMenu{}
+		problems := []MenuProblem{}
+
+		err := walkMenus(roots, func(path string) {
+			m, p := parseMenu(path)
+			problems = append(problems, p...)
+
+			if m != nil {
+				res = append(res, m)
+			}
+		})
+
+		return res, problems, err
+	}
+
+	if len(paths) == 0 {
+		paths = MenuConfigLoaded.Paths
+	} else {
+		for _, v := range paths {
+			if _, err := os.Stat(v); err != nil {
+				problems = append(problems, MenuProblem{Path: v, Message: err.Error()})
+			}
+		}
+	}
+
+	menus, p, err := parseAll(paths)
+	problems = append(problems, p...)
+
+	if err != nil {
+		problems = append(problems, MenuProblem{Path: strings.Join(paths, ", "), Message: err.Error()})
+	}
+
+	// menus outside of the given paths can be referenced as well.
+	known := make(map[string]string)
+
+	if configured, _, err := parseAll(MenuConfigLoaded.Paths); err == nil {
+		for _, v := range configured {
+			known[v.menu.Name] = v.path
+		}
+	}
+
+	names := make(map[string]string)
+
+	for _, v := range menus {
+		if v.menu.Name == "" {
+			continue
+		}
+
+		if other, ok := names[v.menu.Name]; ok {
+			l, c := v.line("name", v.menu.Name)
+			problems = append(problems, MenuProblem{v.path, l, c, fmt.Sprintf("duplicate name %q, also used by %s", v.menu.Name, other)})
+			continue
+		}
+
+		names[v.menu.Name] = v.path
+		known[v.menu.Name] = v.path
+	}
+
+	for _, v := range menus {
+		problems = append(problems, v.check(known)...)
+	}
+
+	slices.SortStableFunc(problems, func(a, b MenuProblem) int {
+		return cmp.Or(strings.Compare(a.Path, b.Path), cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+
+	return problems
+}
+
+func parseMenu(path string) (*parsedMenu, []MenuProblem) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, []MenuProblem{{Path: path, Message: err.Error()}}
+	}
+
+	if filepath.Ext(path) == ".lua" {
+		return parseLuaMenu(path, b)
+	}
+
+	return parseTomlMenu(path, b)
+}
+
+func parseTomlMenu(path string, b []byte) (*parsedMenu, []MenuProblem) {
+	m := &parsedMenu{
+		path: path,
+		line: func(key, value string) (int, int) {
+			return findTomlKey(b, key, value)
+		},
+	}
+
+	err := toml.NewDecoder(bytes.NewReader(b)).DisallowUnknownFields().Decode(&m.menu)
+	if err == nil {
+		return m, nil
+	}
+
+	var strict *toml.StrictMissingError
+
+	if errors.As(err, &strict) {
+		problems := []MenuProblem{}
+
+		for _, v := range strict.Errors {
+			l, c := v.Position()
+			problems = append(problems, MenuProblem{path, l, c, fmt.Sprintf("unknown field %q", strings.Join(v.Key(), "."))})
+		}
+
+		return m, problems
+	}
+
+	if p := tomlProblem(path, err); p != nil {
+		return nil, []MenuProblem{*p}
+	}
+
+	return nil, []MenuProblem{{Path: path, Message: err.Error()}}
+}
+
+// tomlProblem returns the location of a decoding error, if it has one.
+func tomlProblem(path string, err error) *MenuProblem {
+	var decode *toml.DecodeError
+
+	if !errors.As(err, &decode) {
+		return nil
+	}
+
+	l, c := decode.Position()
+
+	return &MenuProblem{path, l, c, strings.TrimPrefix(decode.Error(), "toml: ")}
+}
+
+// findTomlKey returns the position of the first line assigning the key, with the value if it isn't empty.
+// Keys of inline tables are found by their value.
+func findTomlKey(b []byte, key, value string) (int, int) {
+	lines := strings.Split(string(b), "\n")
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		after, ok := strings.CutPrefix(strings.Trim(trimmed, `"`), key)
+		if !ok || !strings.HasPrefix(strings.TrimSpace(strings.TrimPrefix(after, `"`)), "=") {
+			continue
+		}
+
+		if value == "" {
+			return i + 1, len(line) - len(trimmed) + 1
+		}
+
+		if col := strings.Index(line, strconv.Quote(value)); col != -1 {
+			return i + 1, col + 1
+		}
+	}
+
+	if value != "" {
+		for i, line := range lines {
+			if col := strings.Index(line, strconv.Quote(value)); col != -1 {
+				return i + 1, col + 1
+			}
+		}
+	}
+
+	return 0, 0
+}
+
+func parseLuaMenu(path string, b []byte) (*parsedMenu, []MenuProblem) {
+	chunk, err := parse.Parse(bytes.NewReader(b), path)
+	if err != nil {
+		var perr *parse.Error
+
+		if errors.As(err, &perr) {
+			if perr.Pos.Line < 1 {
+				return nil, []MenuProblem{{Path: path, Message: perr.Message + " at end of file"}}
+			}
+
+			return nil, []MenuProblem{{path, perr.Pos.Line, perr.Pos.Column, fmt.Sprintf("%s near %q", perr.Message, perr.Token)}}
+		}
+
+		return nil, []MenuProblem{{Path: path, Message: strings.TrimSpace(err.Error())}}
+	}
+
+	m := &parsedMenu{
+		path: path,
+		menu: Menu{IsLua: true},
+	}
+
+	problems := []MenuProblem{}
+	lines := make(map[string]int)
+
+	for _, stmt := range chunk {
+		switch s := stmt.(type) {
+		case *ast.FuncDefStmt:
+			if ident, ok := s.Name.Func.(*ast.IdentExpr); ok && s.Name.Receiver == nil {
+				m.functions = append(m.functions, ident.Value)
+				lines[ident.Value] = s.Line()
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range s.Lhs {
+				ident, ok := lhs.(*ast.IdentExpr)
+				if !ok || i >= len(s.Rhs) {
+					continue
+				}
+
+				lines[ident.Value] = s.Line()
+
+				t, known := luaExprType(s.Rhs[i])
+
+				if t == lua.LTFunction || !known {
+					m.functions = append(m.functions, ident.Value)
+				}
+
+				if want, ok := luaGlobalTypes[ident.Value]; ok && known && t != want && t != lua.LTNil {
+					problems = append(problems, MenuProblem{path, s.Line(), 0, fmt.Sprintf("%s: expected %s, got %s", ident.Value, want, t)})
+					m.dynamicName = m.dynamicName || ident.Value == "Name" || ident.Value == "NamePretty"
+
+					continue
+				}
+
+				m.setLuaGlobal(ident.Value, s.Rhs[i])
+			}
+		}
+	}
+
+	m.line = func(key, _ string) (int, int) {
+		return lines[luaGlobalName(key)], 0
+	}
+
+	if !slices.Contains(m.functions, "GetEntries") {
+		problems = append(problems, MenuProblem{Path: path, Message: "missing GetEntries function"})
+	}
+
+	return m, problems
+}
+
+// luaExprType returns the type of a constant expression, known is false for anything else.
+func luaExprType(expr ast.Expr) (t lua.LValueType, known bool) {
+	switch expr.(type) {
+	case *ast.StringExpr:
+		return lua.LTString, true
+	case *ast.NumberExpr:
+		return lua.LTNumber, true
+	case *ast.TrueExpr, *ast.FalseExpr:
+		return lua.LTBool, true
+	case *ast.NilExpr:
+		return lua.LTNil, true
+	case *ast.TableExpr:
+		return lua.LTTable, true
+	case *ast.FunctionExpr:
+		return lua.LTFunction, true
+	}
+
+	return lua.LTNil, false
+}
+
+// setLuaGlobal sets the menu fields needed for validation from constant expressions.
+func (m *parsedMenu) setLuaGlobal(name string, expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.StringExpr:
+		switch name {
+		case "Name":
+			m.menu.Name = e.Value
+		case "NamePretty":
+			m.menu.NamePretty = e.Value
+		case "Action":
+			m.menu.Action = e.Value
+		case "Parent":
+			m.menu.Parent = e.Value
+		case "SubMenu":
+			m.menu.SubMenu = e.Value
+		}
+	case *ast.TableExpr:
+		if name == "When" {
+			for _, f := range e.Fields {
+				k, kok := f.Key.(*ast.StringExpr)
+				v, vok := f.Value.(*ast.StringExpr)
+
+				if kok && vok && k.Value == "Lua" {
+					m.menu.When.Lua = v.Value
+				}
+			}
+		}
+
+		if name != "Actions" {
+			return
+		}
+
+		m.menu.Actions = make(map[string]string)
+
+		for _, f := range e.Fields {
+			k, kok := f.Key.(*ast.StringExpr)
+			v, vok := f.Value.(*ast.StringExpr)
+
+			if kok && vok {
+				m.menu.Actions[k.Value] = v.Value
+			}
+		}
+	default:
+		if name == "Name" || name == "NamePretty" {
+			m.dynamicName = true
+		}
+	}
+}
+
+// luaGlobalName maps toml keys to the globals of Lua menus. Other keys are action names.
+func luaGlobalName(key string) string {
+	switch key {
+	case "name":
+		return "Name"
+	case "name_pretty":
+		return "NamePretty"
+	case "parent":
+		return "Parent"
+	case "submenu":
+		return "SubMenu"
+	case "action":
+		return "Action"
+	case "lua":
+		return "When"
+	}
+
+	return "Actions"
+}
+
+// check validates a parsed menu against all known menu names.
+func (m *parsedMenu) check(known map[string]string) []MenuProblem {
+	problems := []MenuProblem{}
+
+	add := func(key, value, msg string) {
+		l, c := m.line(key, value)
+		problems = append(problems, MenuProblem{m.path, l, c, msg})
+	}
+
+	menu := m.menu
+
+	if menu.Name == "" && !m.dynamicName {
+		add("name", "", "missing name")
+	}
+
+	if menu.NamePretty == "" && !m.dynamicName {
+		add("name_pretty", "", "missing name_pretty")
+	}
+
+	reference := func(key, name string) {
+		if name == "" || strings.HasPrefix(name, "dmenu:") {
+			return
+		}
+
+		if _, ok := known[name]; !ok {
+			add(key, name, fmt.Sprintf("%s %q doesn't exist", key, name))
+		}
+	}
+
+	reference("parent", menu.Parent)
+	reference("submenu", menu.SubMenu)
+
+	actions := []string{}
+
+	checkAction := func(key, run string) {
+		after, ok := strings.CutPrefix(run, "lua:")
+		if !ok {
+			return
+		}
+
+		switch {
+		case !menu.IsLua:
+			add(key, run, fmt.Sprintf("%q: lua actions need a Lua menu", run))
+		case !slices.Contains(m.functions, after):
+			add(key, run, fmt.Sprintf("%q: function %s doesn't exist", run, after))
+		}
+	}
+
+	checkAction("action", menu.Action)
+
+	for k, v := range menu.Actions {
+		actions = append(actions, k)
+		checkAction(k, v)
+	}
+
+	for _, e := range menu.Entries {
+		reference("submenu", e.SubMenu)
+
+		for k, v := range e.Actions {
+			actions = append(actions, k)
+			checkAction(k, v)
+		}
+	}
+
+	for _, v := range menu.AsyncActions {
+		if !slices.Contains(actions, v) {
+			add("async_actions", "", fmt.Sprintf("async action %q isn't defined", v))
+		}
+	}
+
+	checkInputs := func(inputs map[string][]Input) {
+		for action, list := range inputs {
+			if action != "default" && !slices.Contains(actions, action) {
+				add(action, "", fmt.Sprintf("inputs for undefined action %q", action))
+			}
+
+			for _, v := range list {
+				switch {
+				case v.Name == "":
+					add("name", "", fmt.Sprintf("input of action %q without name", action))
+				case v.Type != "" && !slices.Contains(InputTypes, v.Type):
+					add("type", v.Type, fmt.Sprintf("input %q: unknown type %q, use %s", v.Name, v.Type, strings.Join(InputTypes, ", ")))
+				case v.Type == "choice" && len(v.Choices) == 0:
+					add("type", v.Type, fmt.Sprintf("input %q: choice without choices", v.Name))
+				case v.Default != "" && v.check(v.Default) != nil:
+					add("default", v.Default, fmt.Sprintf("input %q: invalid default: %s", v.Name, v.check(v.Default)))
+				}
+			}
+		}
+	}
+
+	checkInputs(menu.Inputs)
+
+	checkWhen := func(w When) {
+		switch {
+		case w.Lua == "":
+		case !menu.IsLua:
+			add("lua", w.Lua, fmt.Sprintf("condition %q needs a Lua menu", w.Lua))
+		case !slices.Contains(m.functions, w.Lua):
+			add("lua", w.Lua, fmt.Sprintf("condition: function %s doesn't exist", w.Lua))
+		}
+	}
+
+	checkWhen(menu.When)
+
+	for _, e := range menu.Entries {
+		checkInputs(e.Inputs)
+		checkWhen(e.When)
+	}
+
+	if menu.Generator != "" {
+		switch menu.Format {
+		case "", "lines", "json":
+		case "tsv":
+			for _, v := range []string{menu.Mapping.Text, menu.Mapping.Subtext, menu.Mapping.Value, menu.Mapping.Icon, menu.Mapping.Preview} {
+				if i, err := strconv.Atoi(v); v != "" && (err != nil || i < 1) {
+					add("format", menu.Format, fmt.Sprintf("mapping %q isn't a column, they start at 1", v))
+				}
+			}
+		default:
+			add("format", menu.Format, fmt.Sprintf("unknown format %q, use lines, json or tsv", menu.Format))
+		}
+	}
+
+	return problems
+}
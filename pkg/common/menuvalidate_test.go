@@ -0,0 +1,59 @@
+package common
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateMenu(t *testing.T) {
+	toml, problems := parseTomlMenu("a.toml", []byte(`name = "a"
+name_pretty = "A"
+parent = "missing"
+async_actions = ["undefined"]
+
+[[entries]]
+text = "entry"
+actions = { "run" = "lua:Run" }
+
+[[entries.inputs.run]]
+name = "x"
+type = "slider"
+`))
+	if len(problems) != 0 {
+		t.Fatalf("parseTomlMenu: %v", problems)
+	}
+
+	script, problems := parseLuaMenu("b.lua", []byte(`Name = "b"
+NamePretty = true
+Actions = { run = "lua:Run", other = "lua:Other" }
+
+function GetEntries() return {} end
+function Run() end
+`))
+	if len(problems) != 1 || problems[0].Line != 2 {
+		t.Fatalf("parseLuaMenu: %v", problems)
+	}
+
+	known := map[string]string{"a": "a.toml", "b": "b.lua"}
+
+	want := []MenuProblem{
+		{"a.toml", 3, 10, `parent "missing" doesn't exist`},
+		{"a.toml", 8, 21, `"lua:Run": lua actions need a Lua menu`},
+		{"a.toml", 4, 1, `async action "undefined" isn't defined`},
+		{"a.toml", 12, 8, `input "x": unknown type "slider", use text, number, choice, file, password`},
+	}
+
+	if got := toml.check(known); !slices.Equal(got, want) {
+		t.Errorf("toml: got %v, want %v", got, want)
+	}
+
+	want = []MenuProblem{{"b.lua", 3, 0, `"lua:Other": function Other doesn't exist`}}
+
+	if got := script.check(known); !slices.Equal(got, want) {
+		t.Errorf("lua: got %v, want %v", got, want)
+	}
+
+	if _, problems := parseLuaMenu("c.lua", []byte("Name = \n")); len(problems) != 1 || problems[0].Line != 0 {
+		t.Errorf("syntax error: got %v", problems)
+	}
+}
@@ -0,0 +1,185 @@
+package common
+
+import (
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// When limits a menu or entry to machines matching all set conditions, so one set of menus can be shared.
+type When struct {
+	Binary  string   `toml:"binary" desc:"executable that has to be on PATH"`
+	Env     string   `toml:"env" desc:"environment variable that has to be set, NAME or NAME=value"`
+	Desktop []string `toml:"desktop" desc:"one of them has to be in XDG_CURRENT_DESKTOP, case insensitive"`
+	File    string   `toml:"file" desc:"path that has to exist, ~ is expanded"`
+	Lua     string   `toml:"lua" desc:"function of a Lua menu returning true if visible, entries pass their value"`
+}
+
+func (w When) empty() bool {
+	return w.Binary == "" && w.Env == "" && len(w.Desktop) == 0 && w.File == "" && w.Lua == ""
+}
+
+// Visible reports whether the conditions of the menu are met.
+func (m *Menu) Visible() bool {
+	return m.matches(m.When, nil)
+}
+
+// EntryVisible reports whether the conditions of the entry are met.
+func (m *Menu) EntryVisible(e *Entry) bool {
+	return m.matches(e.When, e)
+}
+
+func (m *Menu) matches(w When, e *Entry) bool {
+	if w.empty() {
+		return true
+	}
+
+	if w.Binary != "" && !cachedCondition("binary:"+w.Binary, func() bool {
+		_, err := exec.LookPath(w.Binary)
+		return err == nil
+	}) {
+		return false
+	}
+
+	if w.Env != "" {
+		name, value, hasValue := strings.Cut(w.Env, "=")
+		val, ok := os.LookupEnv(name)
+
+		if !ok || hasValue && val != value {
+			return false
+		}
+	}
+
+	if len(w.Desktop) != 0 && !cachedCondition("desktop:"+strings.Join(w.Desktop, ":"), func() bool {
+		current := strings.Split(strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP")), ":")
+
+		return slices.ContainsFunc(w.Desktop, func(d string) bool {
+			return slices.Contains(current, strings.ToLower(d))
+		})
+	}) {
+		return false
+	}
+
+	if w.File != "" && !cachedCondition("file:"+w.File, func() bool {
+		path := w.File
+
+		if after, ok := strings.CutPrefix(path, "~/"); ok {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, after)
+		}
+
+		_, err := os.Stat(path)
+		return err == nil
+	}) {
+		return false
+	}
+
+	if w.Lua != "" {
+		return m.luaPredicate(w.Lua, e)
+	}
+
+	return true
+}
+
+// whenCacheTTL is how long the results of binary, desktop and file conditions are kept, so they aren't checked for every menu
+// and entry on every keystroke. Lua conditions are called every time.
+const whenCacheTTL = 10 * time.Second
+
+type whenResult struct {
+	ok      bool
+	checked time.Time
+}
+
+var (
+	whenCache   = make(map[string]whenResult)
+	whenCacheMu sync.Mutex
+)
+
+// cachedCondition returns the cached result of a condition, or checks it if there's none or it's older than whenCacheTTL.
+func cachedCondition(key string, check func() bool) bool {
+	whenCacheMu.Lock()
+	res, ok := whenCache[key]
+	whenCacheMu.Unlock()
+
+	if ok && time.Since(res.checked) < whenCacheTTL {
+		return res.ok
+	}
+
+	res = whenResult{ok: check(), checked: time.Now()}
+
+	whenCacheMu.Lock()
+	whenCache[key] = res
+	whenCacheMu.Unlock()
+
+	return res.ok
+}
+
+// luaPredicate calls a Lua function deciding the visibility. Failing calls hide the menu or entry.
+func (m *Menu) luaPredicate(fn string, e *Entry) bool {
+	if !m.IsLua {
+		slog.Error("menus", "when", "lua conditions need a Lua menu", "menu", m.Name)
+		return false
+	}
+
+	args := []lua.LValue{}
+
+	if e != nil {
+		args = append(args, lua.LString(e.Value))
+	}
+
+	visible := false
+
+	err := m.WithLuaState(func(state *lua.LState) error {
+		if err := state.CallByParam(lua.P{
+			Fn:      state.GetGlobal(fn),
+			NRet:    1,
+			Protect: true,
+		}, args...); err != nil {
+			return err
+		}
+
+		visible = lua.LVAsBool(state.Get(-1))
+		state.Pop(1)
+
+		return nil
+	})
+	if err != nil {
+		slog.Error("menus", "when", err, "menu", m.Name, "function", fn)
+		return false
+	}
+
+	return visible
+}
+
+// luaWhen reads conditions from a Lua table, f.e. { Binary = "niri", Desktop = { "niri" } }.
+func luaWhen(val lua.LValue) When {
+	w := When{}
+
+	table, ok := val.(*lua.LTable)
+	if !ok {
+		return w
+	}
+
+	w.Binary = lua.LVAsString(table.RawGetString("Binary"))
+	w.Env = lua.LVAsString(table.RawGetString("Env"))
+	w.File = lua.LVAsString(table.RawGetString("File"))
+	w.Lua = lua.LVAsString(table.RawGetString("Lua"))
+
+	switch desktop := table.RawGetString("Desktop").(type) {
+	case lua.LString:
+		w.Desktop = []string{string(desktop)}
+	case *lua.LTable:
+		desktop.ForEach(func(_, v lua.LValue) {
+			w.Desktop = append(w.Desktop, lua.LVAsString(v))
+		})
+	}
+
+	return w
+}
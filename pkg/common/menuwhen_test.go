@@ -0,0 +1,60 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWhen(t *testing.T) {
+	t.Setenv("XDG_CURRENT_DESKTOP", "niri:GNOME")
+	t.Setenv("ELEPHANT_TEST", "on")
+
+	dir := t.TempDir()
+	m := &Menu{Name: "test"}
+
+	tests := []struct {
+		when When
+		want bool
+	}{
+		{When{}, true},
+		{When{Binary: "sh"}, true},
+		{When{Binary: "elephant-missing-binary"}, false},
+		{When{Env: "ELEPHANT_TEST"}, true},
+		{When{Env: "ELEPHANT_TEST=off"}, false},
+		{When{Env: "ELEPHANT_MISSING"}, false},
+		{When{Desktop: []string{"Hyprland", "gnome"}}, true},
+		{When{Desktop: []string{"Hyprland"}}, false},
+		{When{File: dir}, true},
+		{When{File: filepath.Join(dir, "missing")}, false},
+		{When{Binary: "sh", Desktop: []string{"Hyprland"}}, false},
+		{When{Lua: "Visible"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.EntryVisible(&Entry{When: tt.when}); got != tt.want {
+			t.Errorf("%+v: got %v, want %v", tt.when, got, tt.want)
+		}
+	}
+}
+
+func TestWhenCached(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "flag")
+
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Menu{Name: "test"}
+	e := &Entry{When: When{File: file}}
+
+	if !m.EntryVisible(e) {
+		t.Fatal("want visible while the file exists")
+	}
+
+	os.Remove(file)
+
+	if !m.EntryVisible(e) {
+		t.Error("want the cached result until it expires")
+	}
+}
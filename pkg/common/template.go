@@ -0,0 +1,95 @@
+package common
+
+import (
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// placeholder matches %NAME%, %NAME:argument% and both followed by modifiers, f.e. %VALUE|shell% or %DATE:2006-01-02|url%.
+var placeholder = regexp.MustCompile(`%([A-Z][A-Z_]*)(?::([^%|]+))?((?:\|[a-z]+)*)%`)
+
+// Expand replaces placeholders in commands or urls with the given values. Placeholders without a value are kept as they are.
+// Values for placeholders with an argument are given as "NAME:argument", f.e. "ARG:minutes" for %ARG:minutes%.
+//
+// Besides the given values, these are always available:
+//   - %CLIPBOARD%: the current clipboard text, unless given
+//   - %ENV:NAME%: the environment variable NAME
+//   - %DATE:layout%: the current time, formatted with a Go layout, f.e. %DATE:2006-01-02 15:04%
+//
+// Modifiers are applied from left to right:
+//   - shell: quotes the value for sh, so quotes in it can't break the command
+//   - url: escapes the value for use in a query string
+//   - trim, lower, upper
+func Expand(tmpl string, values map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(tmpl, func(match string) string {
+		groups := placeholder.FindStringSubmatch(match)
+		name, arg := groups[1], groups[2]
+
+		var val string
+
+		switch {
+		case name == "ENV" && arg != "":
+			val = os.Getenv(arg)
+		case name == "DATE" && arg != "":
+			val = time.Now().Format(arg)
+		case arg != "":
+			var ok bool
+
+			if val, ok = values[name+":"+arg]; !ok {
+				return match
+			}
+		default:
+			var ok bool
+
+			val, ok = values[name]
+
+			if !ok && name == "CLIPBOARD" {
+				val, ok = ClipboardText(), true
+			}
+
+			if !ok {
+				return match
+			}
+		}
+
+		for m := range strings.SplitSeq(strings.TrimPrefix(groups[3], "|"), "|") {
+			switch m {
+			case "":
+			case "shell":
+				val = ShellQuote(val)
+			case "url":
+				val = url.QueryEscape(val)
+			case "trim":
+				val = strings.TrimSpace(val)
+			case "lower":
+				val = strings.ToLower(val)
+			case "upper":
+				val = strings.ToUpper(val)
+			default:
+				slog.Warn("template", "unknown modifier", m, "placeholder", match)
+			}
+		}
+
+		return val
+	})
+}
+
+// UsesPlaceholder reports whether the template contains the placeholder, with or without modifiers.
+func UsesPlaceholder(tmpl, name string) bool {
+	for _, v := range placeholder.FindAllStringSubmatch(tmpl, -1) {
+		if v[1] == name && v[2] == "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShellQuote wraps s in single quotes for sh.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
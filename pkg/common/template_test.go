@@ -0,0 +1,41 @@
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	t.Setenv("ELEPHANT_TEST", "env")
+
+	values := map[string]string{
+		"VALUE": "it's",
+		"QUERY": " a b ",
+		"ARG:n": "5",
+	}
+
+	tests := []struct {
+		tmpl, want string
+	}{
+		{"echo %VALUE%", "echo it's"},
+		{"echo %VALUE|shell%", `echo 'it'\''s'`},
+		{"xdg-open https://x.org/?q=%QUERY|trim|url%", "xdg-open https://x.org/?q=a+b"},
+		{"%QUERY|trim|upper%", "A B"},
+		{"%ENV:ELEPHANT_TEST%", "env"},
+		{"%DATE:2006%", time.Now().Format("2006")},
+		{"%UNKNOWN% %VALUE:x%", "%UNKNOWN% %VALUE:x%"},
+		{`date "+%H:%M"`, `date "+%H:%M"`},
+		{"%H:%VALUE%", "%H:it's"},
+		{"sleep %ARG:n%m %ARG:x%", "sleep 5m %ARG:x%"},
+	}
+
+	for _, tt := range tests {
+		if got := Expand(tt.tmpl, values); got != tt.want {
+			t.Errorf("Expand(%q) = %q, want %q", tt.tmpl, got, tt.want)
+		}
+	}
+
+	if !UsesPlaceholder("echo %VALUE|shell%", "VALUE") || UsesPlaceholder("echo %ARGS%", "VALUE") {
+		t.Error("UsesPlaceholder: wrong result")
+	}
+}
@@ -6,15 +6,17 @@ import (
 	"strings"
 )
 
+// ReplaceResultOrStdinCmd expands %VALUE% with the result, see Expand. If the command doesn't use it, the result is passed via stdin
+// and the command is run as it is, so % signs meant for the command, f.e. in date +%H:%M, are kept.
 func ReplaceResultOrStdinCmd(replace, result string) *exec.Cmd {
-	if !strings.Contains(replace, "%VALUE%") {
+	if !UsesPlaceholder(replace, "VALUE") {
 		cmd := exec.Command("sh", "-c", replace)
 
 		cmd.Stdin = strings.NewReader(result)
 		return cmd
 	}
 
-	return exec.Command("sh", "-c", strings.ReplaceAll(replace, "%VALUE%", result))
+	return exec.Command("sh", "-c", Expand(replace, map[string]string{"VALUE": result}))
 }
 
 func ClipboardText() string {
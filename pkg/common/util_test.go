@@ -0,0 +1,22 @@
+package common
+
+import (
+	"io"
+	"testing"
+)
+
+func TestReplaceResultOrStdinCmd(t *testing.T) {
+	cmd := ReplaceResultOrStdinCmd("date +%H:%M %CLIPBOARD%", "result")
+
+	if cmd.Args[2] != "date +%H:%M %CLIPBOARD%" {
+		t.Errorf("got %q, want the command unchanged", cmd.Args[2])
+	}
+
+	if b, _ := io.ReadAll(cmd.Stdin); string(b) != "result" {
+		t.Errorf("got %q on stdin, want the result", b)
+	}
+
+	if cmd := ReplaceResultOrStdinCmd("wl-copy %VALUE|shell%", "it's"); cmd.Args[2] != `wl-copy 'it'\''s'` || cmd.Stdin != nil {
+		t.Errorf("got %q, want the quoted result in the command", cmd.Args[2])
+	}
+}
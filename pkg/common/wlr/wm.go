@@ -1,8 +1,11 @@
 package wlr
 
 import (
+	"fmt"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/neurlang/wayland/wl"
@@ -17,16 +20,58 @@ var (
 
 type windowmap map[wl.ProxyId]*Window
 
-var windows = make(windowmap)
+// windowsMu guards windows and the fields of its entries, they are written by the wayland event loop.
+var (
+	windows   = make(windowmap)
+	windowsMu sync.RWMutex
+)
 
 var IsRunning = false
 
+// focused is the id of the last activated toplevel.
+var focused atomic.Value
+
+// Focused returns the app id of the focused window, empty if unknown.
+func Focused() string {
+	id, ok := focused.Load().(wl.ProxyId)
+	if !ok {
+		return ""
+	}
+
+	windowsMu.RLock()
+	defer windowsMu.RUnlock()
+
+	if w, ok := windows[id]; ok {
+		return w.AppID
+	}
+
+	return ""
+}
+
+// Windows returns a snapshot of the known windows.
 func Windows() windowmap {
-	return windows
+	windowsMu.RLock()
+	defer windowsMu.RUnlock()
+
+	res := make(windowmap, len(windows))
+
+	for k, v := range windows {
+		res[k] = &Window{Toplevel: v.Toplevel, AppID: v.AppID, Title: v.Title}
+	}
+
+	return res
 }
 
 func Activate(id wl.ProxyId) error {
-	err := windows[id].Toplevel.Activate(seat[len(seat)-1])
+	windowsMu.RLock()
+	w, ok := windows[id]
+	windowsMu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("window %d not found", id)
+	}
+
+	err := w.Toplevel.Activate(seat[len(seat)-1])
 	if err != nil {
 		return err
 	}
@@ -140,8 +185,11 @@ func (*Window) HandleZwlrForeignToplevelManagerV1Toplevel(e ZwlrForeignToplevelM
 	e.Toplevel.AddTitleHandler(handler)
 	e.Toplevel.AddAppIdHandler(handler)
 	e.Toplevel.AddClosedHandler(handler)
+	e.Toplevel.AddStateHandler(handler)
 
+	windowsMu.Lock()
 	windows[e.Toplevel.Id()] = &Window{Toplevel: e.Toplevel}
+	windowsMu.Unlock()
 }
 
 func (h *Window) HandleZwlrForeignToplevelHandleV1Closed(e ZwlrForeignToplevelHandleV1ClosedEvent) {
@@ -151,13 +199,22 @@ func (h *Window) HandleZwlrForeignToplevelHandleV1Closed(e ZwlrForeignToplevelHa
 
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
+
+	windowsMu.Lock()
 	delete(windows, h.Toplevel.Id())
+	windowsMu.Unlock()
 }
 
 func (h *Window) HandleZwlrForeignToplevelHandleV1AppId(e ZwlrForeignToplevelHandleV1AppIdEvent) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
-	windows[h.Toplevel.Id()].AppID = e.AppId
+
+	windowsMu.Lock()
+	if w, ok := windows[h.Toplevel.Id()]; ok {
+		w.AppID = e.AppId
+	}
+	windowsMu.Unlock()
+
 	h.AppID = e.AppId
 
 	if h.AddChan != nil {
@@ -165,8 +222,22 @@ func (h *Window) HandleZwlrForeignToplevelHandleV1AppId(e ZwlrForeignToplevelHan
 	}
 }
 
+func (h *Window) HandleZwlrForeignToplevelHandleV1State(e ZwlrForeignToplevelHandleV1StateEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if slices.Contains(e.State, ZwlrForeignToplevelHandleV1StateActivated) {
+		focused.Store(h.Toplevel.Id())
+	}
+}
+
 func (h *Window) HandleZwlrForeignToplevelHandleV1Title(e ZwlrForeignToplevelHandleV1TitleEvent) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
-	windows[h.Toplevel.Id()].Title = e.Title
+
+	windowsMu.Lock()
+	if w, ok := windows[h.Toplevel.Id()]; ok {
+		w.Title = e.Title
+	}
+	windowsMu.Unlock()
 }
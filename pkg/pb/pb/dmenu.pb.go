@@ -0,0 +1,298 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.1
+// source: dmenu.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DmenuRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Prompt        string                 `protobuf:"bytes,2,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	Items         []*DmenuItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Timeout       int32                  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DmenuRequest) Reset() {
+	*x = DmenuRequest{}
+	mi := &file_dmenu_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DmenuRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmenuRequest) ProtoMessage() {}
+
+func (x *DmenuRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_dmenu_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmenuRequest.ProtoReflect.Descriptor instead.
+func (*DmenuRequest) Descriptor() ([]byte, []int) {
+	return file_dmenu_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DmenuRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DmenuRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *DmenuRequest) GetItems() []*DmenuItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *DmenuRequest) GetTimeout() int32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+type DmenuItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Subtext       string                 `protobuf:"bytes,2,opt,name=subtext,proto3" json:"subtext,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Icon          string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	Preview       string                 `protobuf:"bytes,5,opt,name=preview,proto3" json:"preview,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DmenuItem) Reset() {
+	*x = DmenuItem{}
+	mi := &file_dmenu_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DmenuItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmenuItem) ProtoMessage() {}
+
+func (x *DmenuItem) ProtoReflect() protoreflect.Message {
+	mi := &file_dmenu_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmenuItem.ProtoReflect.Descriptor instead.
+func (*DmenuItem) Descriptor() ([]byte, []int) {
+	return file_dmenu_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DmenuItem) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DmenuItem) GetSubtext() string {
+	if x != nil {
+		return x.Subtext
+	}
+	return ""
+}
+
+func (x *DmenuItem) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *DmenuItem) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *DmenuItem) GetPreview() string {
+	if x != nil {
+		return x.Preview
+	}
+	return ""
+}
+
+type DmenuResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Selected      bool                   `protobuf:"varint,1,opt,name=selected,proto3" json:"selected,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DmenuResponse) Reset() {
+	*x = DmenuResponse{}
+	mi := &file_dmenu_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DmenuResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DmenuResponse) ProtoMessage() {}
+
+func (x *DmenuResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_dmenu_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DmenuResponse.ProtoReflect.Descriptor instead.
+func (*DmenuResponse) Descriptor() ([]byte, []int) {
+	return file_dmenu_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DmenuResponse) GetSelected() bool {
+	if x != nil {
+		return x.Selected
+	}
+	return false
+}
+
+func (x *DmenuResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DmenuResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+var File_dmenu_proto protoreflect.FileDescriptor
+
+const file_dmenu_proto_rawDesc = "" +
+	"\n" +
+	"\vdmenu.proto\x12\x02pb\"y\n" +
+	"\fDmenuRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prompt\x18\x02 \x01(\tR\x06prompt\x12#\n" +
+	"\x05items\x18\x03 \x03(\v2\r.pb.DmenuItemR\x05items\x12\x18\n" +
+	"\atimeout\x18\x04 \x01(\x05R\atimeout\"}\n" +
+	"\tDmenuItem\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x18\n" +
+	"\asubtext\x18\x02 \x01(\tR\asubtext\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x12\n" +
+	"\x04icon\x18\x04 \x01(\tR\x04icon\x12\x18\n" +
+	"\apreview\x18\x05 \x01(\tR\apreview\"U\n" +
+	"\rDmenuResponse\x12\x1a\n" +
+	"\bselected\x18\x01 \x01(\bR\bselected\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05valueB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_dmenu_proto_rawDescOnce sync.Once
+	file_dmenu_proto_rawDescData []byte
+)
+
+func file_dmenu_proto_rawDescGZIP() []byte {
+	file_dmenu_proto_rawDescOnce.Do(func() {
+		file_dmenu_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_dmenu_proto_rawDesc), len(file_dmenu_proto_rawDesc)))
+	})
+	return file_dmenu_proto_rawDescData
+}
+
+var file_dmenu_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_dmenu_proto_goTypes = []any{
+	(*DmenuRequest)(nil),  // 0: pb.DmenuRequest
+	(*DmenuItem)(nil),     // 1: pb.DmenuItem
+	(*DmenuResponse)(nil), // 2: pb.DmenuResponse
+}
+var file_dmenu_proto_depIdxs = []int32{
+	1, // 0: pb.DmenuRequest.items:type_name -> pb.DmenuItem
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_dmenu_proto_init() }
+func file_dmenu_proto_init() {
+	if File_dmenu_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_dmenu_proto_rawDesc), len(file_dmenu_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_dmenu_proto_goTypes,
+		DependencyIndexes: file_dmenu_proto_depIdxs,
+		MessageInfos:      file_dmenu_proto_msgTypes,
+	}.Build()
+	File_dmenu_proto = out.File
+	file_dmenu_proto_goTypes = nil
+	file_dmenu_proto_depIdxs = nil
+}
@@ -0,0 +1,472 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.1
+// source: history.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type HistoryRequest_Action int32
+
+const (
+	HistoryRequest_LIST   HistoryRequest_Action = 0
+	HistoryRequest_PRUNE  HistoryRequest_Action = 1
+	HistoryRequest_CLEAR  HistoryRequest_Action = 2
+	HistoryRequest_EXPORT HistoryRequest_Action = 3
+	HistoryRequest_IMPORT HistoryRequest_Action = 4
+)
+
+// Enum value maps for HistoryRequest_Action.
+var (
+	HistoryRequest_Action_name = map[int32]string{
+		0: "LIST",
+		1: "PRUNE",
+		2: "CLEAR",
+		3: "EXPORT",
+		4: "IMPORT",
+	}
+	HistoryRequest_Action_value = map[string]int32{
+		"LIST":   0,
+		"PRUNE":  1,
+		"CLEAR":  2,
+		"EXPORT": 3,
+		"IMPORT": 4,
+	}
+)
+
+func (x HistoryRequest_Action) Enum() *HistoryRequest_Action {
+	p := new(HistoryRequest_Action)
+	*p = x
+	return p
+}
+
+func (x HistoryRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HistoryRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_history_proto_enumTypes[0].Descriptor()
+}
+
+func (HistoryRequest_Action) Type() protoreflect.EnumType {
+	return &file_history_proto_enumTypes[0]
+}
+
+func (x HistoryRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryRequest_Action.Descriptor instead.
+func (HistoryRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Action        HistoryRequest_Action  `protobuf:"varint,2,opt,name=action,proto3,enum=pb.HistoryRequest_Action" json:"action,omitempty"`
+	Events        []*HistoryEvent        `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_history_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HistoryRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetAction() HistoryRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return HistoryRequest_LIST
+}
+
+func (x *HistoryRequest) GetEvents() []*HistoryEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type HistoryEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	Identifier    string                 `protobuf:"bytes,3,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Used          int64                  `protobuf:"varint,4,opt,name=used,proto3" json:"used,omitempty"`
+	App           string                 `protobuf:"bytes,5,opt,name=app,proto3" json:"app,omitempty"`
+	Workspace     string                 `protobuf:"bytes,6,opt,name=workspace,proto3" json:"workspace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryEvent) Reset() {
+	*x = HistoryEvent{}
+	mi := &file_history_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryEvent) ProtoMessage() {}
+
+func (x *HistoryEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryEvent.ProtoReflect.Descriptor instead.
+func (*HistoryEvent) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *HistoryEvent) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *HistoryEvent) GetApp() string {
+	if x != nil {
+		return x.App
+	}
+	return ""
+}
+
+func (x *HistoryEvent) GetWorkspace() string {
+	if x != nil {
+		return x.Workspace
+	}
+	return ""
+}
+
+type HistoryItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Identifier    string                 `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Uses          int32                  `protobuf:"varint,3,opt,name=uses,proto3" json:"uses,omitempty"`
+	LastUsed      int64                  `protobuf:"varint,4,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	Frecency      float64                `protobuf:"fixed64,5,opt,name=frecency,proto3" json:"frecency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryItem) Reset() {
+	*x = HistoryItem{}
+	mi := &file_history_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryItem) ProtoMessage() {}
+
+func (x *HistoryItem) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryItem.ProtoReflect.Descriptor instead.
+func (*HistoryItem) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HistoryItem) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *HistoryItem) GetIdentifier() string {
+	if x != nil {
+		return x.Identifier
+	}
+	return ""
+}
+
+func (x *HistoryItem) GetUses() int32 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+func (x *HistoryItem) GetLastUsed() int64 {
+	if x != nil {
+		return x.LastUsed
+	}
+	return 0
+}
+
+func (x *HistoryItem) GetFrecency() float64 {
+	if x != nil {
+		return x.Frecency
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Items         []*HistoryItem         `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	Events        []*HistoryEvent        `protobuf:"bytes,5,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_history_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_history_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_history_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HistoryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *HistoryResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *HistoryResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *HistoryResponse) GetItems() []*HistoryItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetEvents() []*HistoryEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+var File_history_proto protoreflect.FileDescriptor
+
+const file_history_proto_rawDesc = "" +
+	"\n" +
+	"\rhistory.proto\x12\x02pb\"\xcb\x01\n" +
+	"\x0eHistoryRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x121\n" +
+	"\x06action\x18\x02 \x01(\x0e2\x19.pb.HistoryRequest.ActionR\x06action\x12(\n" +
+	"\x06events\x18\x03 \x03(\v2\x10.pb.HistoryEventR\x06events\"@\n" +
+	"\x06Action\x12\b\n" +
+	"\x04LIST\x10\x00\x12\t\n" +
+	"\x05PRUNE\x10\x01\x12\t\n" +
+	"\x05CLEAR\x10\x02\x12\n" +
+	"\n" +
+	"\x06EXPORT\x10\x03\x12\n" +
+	"\n" +
+	"\x06IMPORT\x10\x04\"\xa4\x01\n" +
+	"\fHistoryEvent\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x14\n" +
+	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x03 \x01(\tR\n" +
+	"identifier\x12\x12\n" +
+	"\x04used\x18\x04 \x01(\x03R\x04used\x12\x10\n" +
+	"\x03app\x18\x05 \x01(\tR\x03app\x12\x1c\n" +
+	"\tworkspace\x18\x06 \x01(\tR\tworkspace\"\x96\x01\n" +
+	"\vHistoryItem\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x1e\n" +
+	"\n" +
+	"identifier\x18\x02 \x01(\tR\n" +
+	"identifier\x12\x12\n" +
+	"\x04uses\x18\x03 \x01(\x05R\x04uses\x12\x1b\n" +
+	"\tlast_used\x18\x04 \x01(\x03R\blastUsed\x12\x1a\n" +
+	"\bfrecency\x18\x05 \x01(\x01R\bfrecency\"\xa8\x01\n" +
+	"\x0fHistoryResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x12%\n" +
+	"\x05items\x18\x04 \x03(\v2\x0f.pb.HistoryItemR\x05items\x12(\n" +
+	"\x06events\x18\x05 \x03(\v2\x10.pb.HistoryEventR\x06eventsB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_history_proto_rawDescOnce sync.Once
+	file_history_proto_rawDescData []byte
+)
+
+func file_history_proto_rawDescGZIP() []byte {
+	file_history_proto_rawDescOnce.Do(func() {
+		file_history_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_history_proto_rawDesc), len(file_history_proto_rawDesc)))
+	})
+	return file_history_proto_rawDescData
+}
+
+var file_history_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_history_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_history_proto_goTypes = []any{
+	(HistoryRequest_Action)(0), // 0: pb.HistoryRequest.Action
+	(*HistoryRequest)(nil),     // 1: pb.HistoryRequest
+	(*HistoryEvent)(nil),       // 2: pb.HistoryEvent
+	(*HistoryItem)(nil),        // 3: pb.HistoryItem
+	(*HistoryResponse)(nil),    // 4: pb.HistoryResponse
+}
+var file_history_proto_depIdxs = []int32{
+	0, // 0: pb.HistoryRequest.action:type_name -> pb.HistoryRequest.Action
+	2, // 1: pb.HistoryRequest.events:type_name -> pb.HistoryEvent
+	3, // 2: pb.HistoryResponse.items:type_name -> pb.HistoryItem
+	2, // 3: pb.HistoryResponse.events:type_name -> pb.HistoryEvent
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_history_proto_init() }
+func file_history_proto_init() {
+	if File_history_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_history_proto_rawDesc), len(file_history_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_history_proto_goTypes,
+		DependencyIndexes: file_history_proto_depIdxs,
+		EnumInfos:         file_history_proto_enumTypes,
+		MessageInfos:      file_history_proto_msgTypes,
+	}.Build()
+	File_history_proto = out.File
+	file_history_proto_goTypes = nil
+	file_history_proto_depIdxs = nil
+}
@@ -0,0 +1,233 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.1
+// source: incognito.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type IncognitoRequest_Action int32
+
+const (
+	IncognitoRequest_STATUS  IncognitoRequest_Action = 0
+	IncognitoRequest_ENABLE  IncognitoRequest_Action = 1
+	IncognitoRequest_DISABLE IncognitoRequest_Action = 2
+	IncognitoRequest_TOGGLE  IncognitoRequest_Action = 3
+)
+
+// Enum value maps for IncognitoRequest_Action.
+var (
+	IncognitoRequest_Action_name = map[int32]string{
+		0: "STATUS",
+		1: "ENABLE",
+		2: "DISABLE",
+		3: "TOGGLE",
+	}
+	IncognitoRequest_Action_value = map[string]int32{
+		"STATUS":  0,
+		"ENABLE":  1,
+		"DISABLE": 2,
+		"TOGGLE":  3,
+	}
+)
+
+func (x IncognitoRequest_Action) Enum() *IncognitoRequest_Action {
+	p := new(IncognitoRequest_Action)
+	*p = x
+	return p
+}
+
+func (x IncognitoRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (IncognitoRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_incognito_proto_enumTypes[0].Descriptor()
+}
+
+func (IncognitoRequest_Action) Type() protoreflect.EnumType {
+	return &file_incognito_proto_enumTypes[0]
+}
+
+func (x IncognitoRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use IncognitoRequest_Action.Descriptor instead.
+func (IncognitoRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_incognito_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type IncognitoRequest struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Action        IncognitoRequest_Action `protobuf:"varint,1,opt,name=action,proto3,enum=pb.IncognitoRequest_Action" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncognitoRequest) Reset() {
+	*x = IncognitoRequest{}
+	mi := &file_incognito_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncognitoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncognitoRequest) ProtoMessage() {}
+
+func (x *IncognitoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_incognito_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncognitoRequest.ProtoReflect.Descriptor instead.
+func (*IncognitoRequest) Descriptor() ([]byte, []int) {
+	return file_incognito_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *IncognitoRequest) GetAction() IncognitoRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return IncognitoRequest_STATUS
+}
+
+type IncognitoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Incognito     bool                   `protobuf:"varint,1,opt,name=incognito,proto3" json:"incognito,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncognitoResponse) Reset() {
+	*x = IncognitoResponse{}
+	mi := &file_incognito_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncognitoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncognitoResponse) ProtoMessage() {}
+
+func (x *IncognitoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_incognito_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncognitoResponse.ProtoReflect.Descriptor instead.
+func (*IncognitoResponse) Descriptor() ([]byte, []int) {
+	return file_incognito_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *IncognitoResponse) GetIncognito() bool {
+	if x != nil {
+		return x.Incognito
+	}
+	return false
+}
+
+var File_incognito_proto protoreflect.FileDescriptor
+
+const file_incognito_proto_rawDesc = "" +
+	"\n" +
+	"\x0fincognito.proto\x12\x02pb\"\x82\x01\n" +
+	"\x10IncognitoRequest\x123\n" +
+	"\x06action\x18\x01 \x01(\x0e2\x1b.pb.IncognitoRequest.ActionR\x06action\"9\n" +
+	"\x06Action\x12\n" +
+	"\n" +
+	"\x06STATUS\x10\x00\x12\n" +
+	"\n" +
+	"\x06ENABLE\x10\x01\x12\v\n" +
+	"\aDISABLE\x10\x02\x12\n" +
+	"\n" +
+	"\x06TOGGLE\x10\x03\"1\n" +
+	"\x11IncognitoResponse\x12\x1c\n" +
+	"\tincognito\x18\x01 \x01(\bR\tincognitoB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_incognito_proto_rawDescOnce sync.Once
+	file_incognito_proto_rawDescData []byte
+)
+
+func file_incognito_proto_rawDescGZIP() []byte {
+	file_incognito_proto_rawDescOnce.Do(func() {
+		file_incognito_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_incognito_proto_rawDesc), len(file_incognito_proto_rawDesc)))
+	})
+	return file_incognito_proto_rawDescData
+}
+
+var file_incognito_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_incognito_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_incognito_proto_goTypes = []any{
+	(IncognitoRequest_Action)(0), // 0: pb.IncognitoRequest.Action
+	(*IncognitoRequest)(nil),     // 1: pb.IncognitoRequest
+	(*IncognitoResponse)(nil),    // 2: pb.IncognitoResponse
+}
+var file_incognito_proto_depIdxs = []int32{
+	0, // 0: pb.IncognitoRequest.action:type_name -> pb.IncognitoRequest.Action
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_incognito_proto_init() }
+func file_incognito_proto_init() {
+	if File_incognito_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_incognito_proto_rawDesc), len(file_incognito_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_incognito_proto_goTypes,
+		DependencyIndexes: file_incognito_proto_depIdxs,
+		EnumInfos:         file_incognito_proto_enumTypes,
+		MessageInfos:      file_incognito_proto_msgTypes,
+	}.Build()
+	File_incognito_proto = out.File
+	file_incognito_proto_goTypes = nil
+	file_incognito_proto_depIdxs = nil
+}
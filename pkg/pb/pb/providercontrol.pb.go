@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.1
+// source: providercontrol.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ProviderControlRequest_Action int32
+
+const (
+	ProviderControlRequest_ENABLE  ProviderControlRequest_Action = 0
+	ProviderControlRequest_DISABLE ProviderControlRequest_Action = 1
+	ProviderControlRequest_RELOAD  ProviderControlRequest_Action = 2
+)
+
+// Enum value maps for ProviderControlRequest_Action.
+var (
+	ProviderControlRequest_Action_name = map[int32]string{
+		0: "ENABLE",
+		1: "DISABLE",
+		2: "RELOAD",
+	}
+	ProviderControlRequest_Action_value = map[string]int32{
+		"ENABLE":  0,
+		"DISABLE": 1,
+		"RELOAD":  2,
+	}
+)
+
+func (x ProviderControlRequest_Action) Enum() *ProviderControlRequest_Action {
+	p := new(ProviderControlRequest_Action)
+	*p = x
+	return p
+}
+
+func (x ProviderControlRequest_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProviderControlRequest_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_providercontrol_proto_enumTypes[0].Descriptor()
+}
+
+func (ProviderControlRequest_Action) Type() protoreflect.EnumType {
+	return &file_providercontrol_proto_enumTypes[0]
+}
+
+func (x ProviderControlRequest_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProviderControlRequest_Action.Descriptor instead.
+func (ProviderControlRequest_Action) EnumDescriptor() ([]byte, []int) {
+	return file_providercontrol_proto_rawDescGZIP(), []int{0, 0}
+}
+
+type ProviderControlRequest struct {
+	state         protoimpl.MessageState        `protogen:"open.v1"`
+	Provider      string                        `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Action        ProviderControlRequest_Action `protobuf:"varint,2,opt,name=action,proto3,enum=pb.ProviderControlRequest_Action" json:"action,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderControlRequest) Reset() {
+	*x = ProviderControlRequest{}
+	mi := &file_providercontrol_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderControlRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderControlRequest) ProtoMessage() {}
+
+func (x *ProviderControlRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_providercontrol_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderControlRequest.ProtoReflect.Descriptor instead.
+func (*ProviderControlRequest) Descriptor() ([]byte, []int) {
+	return file_providercontrol_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ProviderControlRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderControlRequest) GetAction() ProviderControlRequest_Action {
+	if x != nil {
+		return x.Action
+	}
+	return ProviderControlRequest_ENABLE
+}
+
+type ProviderControlResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderControlResponse) Reset() {
+	*x = ProviderControlResponse{}
+	mi := &file_providercontrol_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderControlResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderControlResponse) ProtoMessage() {}
+
+func (x *ProviderControlResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_providercontrol_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderControlResponse.ProtoReflect.Descriptor instead.
+func (*ProviderControlResponse) Descriptor() ([]byte, []int) {
+	return file_providercontrol_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProviderControlResponse) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderControlResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ProviderControlResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_providercontrol_proto protoreflect.FileDescriptor
+
+const file_providercontrol_proto_rawDesc = "" +
+	"\n" +
+	"\x15providercontrol.proto\x12\x02pb\"\x9e\x01\n" +
+	"\x16ProviderControlRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x129\n" +
+	"\x06action\x18\x02 \x01(\x0e2!.pb.ProviderControlRequest.ActionR\x06action\"-\n" +
+	"\x06Action\x12\n" +
+	"\n" +
+	"\x06ENABLE\x10\x00\x12\v\n" +
+	"\aDISABLE\x10\x01\x12\n" +
+	"\n" +
+	"\x06RELOAD\x10\x02\"e\n" +
+	"\x17ProviderControlResponse\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05errorB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_providercontrol_proto_rawDescOnce sync.Once
+	file_providercontrol_proto_rawDescData []byte
+)
+
+func file_providercontrol_proto_rawDescGZIP() []byte {
+	file_providercontrol_proto_rawDescOnce.Do(func() {
+		file_providercontrol_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_providercontrol_proto_rawDesc), len(file_providercontrol_proto_rawDesc)))
+	})
+	return file_providercontrol_proto_rawDescData
+}
+
+var file_providercontrol_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_providercontrol_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_providercontrol_proto_goTypes = []any{
+	(ProviderControlRequest_Action)(0), // 0: pb.ProviderControlRequest.Action
+	(*ProviderControlRequest)(nil),     // 1: pb.ProviderControlRequest
+	(*ProviderControlResponse)(nil),    // 2: pb.ProviderControlResponse
+}
+var file_providercontrol_proto_depIdxs = []int32{
+	0, // 0: pb.ProviderControlRequest.action:type_name -> pb.ProviderControlRequest.Action
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_providercontrol_proto_init() }
+func file_providercontrol_proto_init() {
+	if File_providercontrol_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_providercontrol_proto_rawDesc), len(file_providercontrol_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_providercontrol_proto_goTypes,
+		DependencyIndexes: file_providercontrol_proto_depIdxs,
+		EnumInfos:         file_providercontrol_proto_enumTypes,
+		MessageInfos:      file_providercontrol_proto_msgTypes,
+	}.Build()
+	File_providercontrol_proto = out.File
+	file_providercontrol_proto_goTypes = nil
+	file_providercontrol_proto_depIdxs = nil
+}
@@ -70,6 +70,7 @@ type ProviderStateResponse struct {
 	States        []string               `protobuf:"bytes,1,rep,name=states,proto3" json:"states,omitempty"`
 	Actions       []string               `protobuf:"bytes,2,rep,name=actions,proto3" json:"actions,omitempty"`
 	Provider      string                 `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Inputs        []*ActionInputs        `protobuf:"bytes,4,rep,name=inputs,proto3" json:"inputs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,17 +126,25 @@ func (x *ProviderStateResponse) GetProvider() string {
 	return ""
 }
 
+func (x *ProviderStateResponse) GetInputs() []*ActionInputs {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
 var File_providerstate_proto protoreflect.FileDescriptor
 
 const file_providerstate_proto_rawDesc = "" +
 	"\n" +
-	"\x13providerstate.proto\x12\x02pb\"2\n" +
+	"\x13providerstate.proto\x12\x02pb\x1a\vinput.proto\"2\n" +
 	"\x14ProviderStateRequest\x12\x1a\n" +
-	"\bprovider\x18\x01 \x01(\tR\bprovider\"e\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\"\x8f\x01\n" +
 	"\x15ProviderStateResponse\x12\x16\n" +
 	"\x06states\x18\x01 \x03(\tR\x06states\x12\x18\n" +
 	"\aactions\x18\x02 \x03(\tR\aactions\x12\x1a\n" +
-	"\bprovider\x18\x03 \x01(\tR\bproviderB\x06Z\x04./pbb\x06proto3"
+	"\bprovider\x18\x03 \x01(\tR\bprovider\x12(\n" +
+	"\x06inputs\x18\x04 \x03(\v2\x10.pb.ActionInputsR\x06inputsB\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_providerstate_proto_rawDescOnce sync.Once
@@ -153,13 +162,15 @@ var file_providerstate_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_providerstate_proto_goTypes = []any{
 	(*ProviderStateRequest)(nil),  // 0: pb.ProviderStateRequest
 	(*ProviderStateResponse)(nil), // 1: pb.ProviderStateResponse
+	(*ActionInputs)(nil),          // 2: pb.ActionInputs
 }
 var file_providerstate_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: pb.ProviderStateResponse.inputs:type_name -> pb.ActionInputs
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
 }
 
 func init() { file_providerstate_proto_init() }
@@ -167,6 +178,7 @@ func file_providerstate_proto_init() {
 	if File_providerstate_proto != nil {
 		return
 	}
+	file_input_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -73,6 +73,7 @@ type QueryRequest struct {
 	Query         string                 `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
 	Maxresults    int32                  `protobuf:"varint,3,opt,name=maxresults,proto3" json:"maxresults,omitempty"`
 	Exactsearch   bool                   `protobuf:"varint,4,opt,name=exactsearch,proto3" json:"exactsearch,omitempty"`
+	Matcher       string                 `protobuf:"bytes,5,opt,name=matcher,proto3" json:"matcher,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -135,6 +136,13 @@ func (x *QueryRequest) GetExactsearch() bool {
 	return false
 }
 
+func (x *QueryRequest) GetMatcher() string {
+	if x != nil {
+		return x.Matcher
+	}
+	return ""
+}
+
 type QueryResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
@@ -210,6 +218,7 @@ type QueryResponse_Item struct {
 	PreviewType   string                        `protobuf:"bytes,11,opt,name=preview_type,json=previewType,proto3" json:"preview_type,omitempty"`
 	State         []string                      `protobuf:"bytes,12,rep,name=state,proto3" json:"state,omitempty"`
 	Actions       []string                      `protobuf:"bytes,13,rep,name=actions,proto3" json:"actions,omitempty"`
+	Inputs        []*ActionInputs               `protobuf:"bytes,14,rep,name=inputs,proto3" json:"inputs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -335,6 +344,13 @@ func (x *QueryResponse_Item) GetActions() []string {
 	return nil
 }
 
+func (x *QueryResponse_Item) GetInputs() []*ActionInputs {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
 type QueryResponse_Item_FuzzyInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
@@ -399,18 +415,19 @@ var File_query_proto protoreflect.FileDescriptor
 
 const file_query_proto_rawDesc = "" +
 	"\n" +
-	"\vquery.proto\x12\x02pb\"\x84\x01\n" +
+	"\vquery.proto\x12\x02pb\x1a\vinput.proto\"\x9e\x01\n" +
 	"\fQueryRequest\x12\x1c\n" +
 	"\tproviders\x18\x01 \x03(\tR\tproviders\x12\x14\n" +
 	"\x05query\x18\x02 \x01(\tR\x05query\x12\x1e\n" +
 	"\n" +
 	"maxresults\x18\x03 \x01(\x05R\n" +
 	"maxresults\x12 \n" +
-	"\vexactsearch\x18\x04 \x01(\bR\vexactsearch\"\xeb\x04\n" +
+	"\vexactsearch\x18\x04 \x01(\bR\vexactsearch\x12\x18\n" +
+	"\amatcher\x18\x05 \x01(\tR\amatcher\"\x95\x05\n" +
 	"\rQueryResponse\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12*\n" +
 	"\x04item\x18\x02 \x01(\v2\x16.pb.QueryResponse.ItemR\x04item\x12\x10\n" +
-	"\x03qid\x18\x03 \x01(\x05R\x03qid\x1a\xe6\x03\n" +
+	"\x03qid\x18\x03 \x01(\x05R\x03qid\x1a\x90\x04\n" +
 	"\x04Item\x12\x1e\n" +
 	"\n" +
 	"identifier\x18\x01 \x01(\tR\n" +
@@ -427,7 +444,8 @@ const file_query_proto_rawDesc = "" +
 	" \x01(\tR\apreview\x12!\n" +
 	"\fpreview_type\x18\v \x01(\tR\vpreviewType\x12\x14\n" +
 	"\x05state\x18\f \x03(\tR\x05state\x12\x18\n" +
-	"\aactions\x18\r \x03(\tR\aactions\x1aU\n" +
+	"\aactions\x18\r \x03(\tR\aactions\x12(\n" +
+	"\x06inputs\x18\x0e \x03(\v2\x10.pb.ActionInputsR\x06inputs\x1aU\n" +
 	"\tFuzzyInfo\x12\x14\n" +
 	"\x05start\x18\x01 \x01(\x05R\x05start\x12\x14\n" +
 	"\x05field\x18\x02 \x01(\tR\x05field\x12\x1c\n" +
@@ -456,16 +474,18 @@ var file_query_proto_goTypes = []any{
 	(*QueryResponse)(nil),                // 2: pb.QueryResponse
 	(*QueryResponse_Item)(nil),           // 3: pb.QueryResponse.Item
 	(*QueryResponse_Item_FuzzyInfo)(nil), // 4: pb.QueryResponse.Item.FuzzyInfo
+	(*ActionInputs)(nil),                 // 5: pb.ActionInputs
 }
 var file_query_proto_depIdxs = []int32{
 	3, // 0: pb.QueryResponse.item:type_name -> pb.QueryResponse.Item
 	4, // 1: pb.QueryResponse.Item.fuzzyinfo:type_name -> pb.QueryResponse.Item.FuzzyInfo
 	0, // 2: pb.QueryResponse.Item.type:type_name -> pb.QueryResponse.Type
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 3: pb.QueryResponse.Item.inputs:type_name -> pb.ActionInputs
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_query_proto_init() }
@@ -473,6 +493,7 @@ func file_query_proto_init() {
 	if File_query_proto != nil {
 		return
 	}
+	file_input_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
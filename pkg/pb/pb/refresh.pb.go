@@ -0,0 +1,122 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        v6.32.1
+// source: refresh.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RefreshRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
+	mi := &file_refresh_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshRequest) ProtoMessage() {}
+
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_refresh_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
+	return file_refresh_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RefreshRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+var File_refresh_proto protoreflect.FileDescriptor
+
+const file_refresh_proto_rawDesc = "" +
+	"\n" +
+	"\rrefresh.proto\x12\x02pb\",\n" +
+	"\x0eRefreshRequest\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bproviderB\x06Z\x04./pbb\x06proto3"
+
+var (
+	file_refresh_proto_rawDescOnce sync.Once
+	file_refresh_proto_rawDescData []byte
+)
+
+func file_refresh_proto_rawDescGZIP() []byte {
+	file_refresh_proto_rawDescOnce.Do(func() {
+		file_refresh_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_refresh_proto_rawDesc), len(file_refresh_proto_rawDesc)))
+	})
+	return file_refresh_proto_rawDescData
+}
+
+var file_refresh_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_refresh_proto_goTypes = []any{
+	(*RefreshRequest)(nil), // 0: pb.RefreshRequest
+}
+var file_refresh_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_refresh_proto_init() }
+func file_refresh_proto_init() {
+	if File_refresh_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_refresh_proto_rawDesc), len(file_refresh_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_refresh_proto_goTypes,
+		DependencyIndexes: file_refresh_proto_depIdxs,
+		MessageInfos:      file_refresh_proto_msgTypes,
+	}.Build()
+	File_refresh_proto = out.File
+	file_refresh_proto_goTypes = nil
+	file_refresh_proto_depIdxs = nil
+}